@@ -343,6 +343,13 @@ type policyAuthorizerRadixLeaf struct {
 // getPolicy first attempts to get an exact match for the segment from the "exact" tree and then falls
 // back to getting the policy for the longest prefix from the "prefix" tree
 func getPolicy(segment string, tree *radix.Tree) (policy interface{}, found bool) {
+	policy, _, _, found = getPolicyMatch(segment, tree)
+	return
+}
+
+// getPolicyMatch works like getPolicy but additionally returns the path of the
+// matching rule and whether it was an exact or a prefix match.
+func getPolicyMatch(segment string, tree *radix.Tree) (policy interface{}, match string, exact bool, found bool) {
 	found = false
 
 	tree.WalkPath(segment, func(path string, leaf interface{}) bool {
@@ -350,12 +357,15 @@ func getPolicy(segment string, tree *radix.Tree) (policy interface{}, found bool
 		if policies.exact != nil && path == segment {
 			found = true
 			policy = policies.exact
+			match = path
+			exact = true
 			return true
 		}
 
 		if policies.prefix != nil {
 			found = true
 			policy = policies.prefix
+			match = path
 		}
 		return false
 	})
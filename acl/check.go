@@ -0,0 +1,168 @@
+package acl
+
+import (
+	"fmt"
+
+	"github.com/armon/go-radix"
+)
+
+// Resource names accepted by Check and PolicyAuthorizer.MatchingRule. They
+// are named after the policy rules that grant access to the resource.
+const (
+	ResourceACL       = "acl"
+	ResourceAgent     = "agent"
+	ResourceEvent     = "event"
+	ResourceIntention = "intention"
+	ResourceKey       = "key"
+	ResourceKeyring   = "keyring"
+	ResourceNode      = "node"
+	ResourceOperator  = "operator"
+	ResourceQuery     = "query"
+	ResourceService   = "service"
+	ResourceSession   = "session"
+)
+
+// Check asks the authorizer whether the given access level is granted on a
+// resource. The segment is the name of the object within the resource, such
+// as a key or a service name, and is ignored for the acl, keyring and
+// operator resources. Sentinel code policies are not executed as there is no
+// request data to run them against.
+func Check(authz Authorizer, resource, segment, access string) (bool, error) {
+	switch access {
+	case PolicyRead, PolicyWrite:
+	case PolicyList:
+		if resource != ResourceKey {
+			return false, fmt.Errorf("Access level %q is only valid for the %q resource", access, ResourceKey)
+		}
+	default:
+		return false, fmt.Errorf("Invalid access level %q", access)
+	}
+	write := access == PolicyWrite
+
+	switch resource {
+	case ResourceACL:
+		if write {
+			return authz.ACLWrite(), nil
+		}
+		return authz.ACLRead(), nil
+	case ResourceAgent:
+		if write {
+			return authz.AgentWrite(segment), nil
+		}
+		return authz.AgentRead(segment), nil
+	case ResourceEvent:
+		if write {
+			return authz.EventWrite(segment), nil
+		}
+		return authz.EventRead(segment), nil
+	case ResourceIntention:
+		if write {
+			return authz.IntentionWrite(segment), nil
+		}
+		return authz.IntentionRead(segment), nil
+	case ResourceKey:
+		switch access {
+		case PolicyWrite:
+			return authz.KeyWrite(segment, nil), nil
+		case PolicyList:
+			return authz.KeyList(segment), nil
+		}
+		return authz.KeyRead(segment), nil
+	case ResourceKeyring:
+		if write {
+			return authz.KeyringWrite(), nil
+		}
+		return authz.KeyringRead(), nil
+	case ResourceNode:
+		if write {
+			return authz.NodeWrite(segment, nil), nil
+		}
+		return authz.NodeRead(segment), nil
+	case ResourceOperator:
+		if write {
+			return authz.OperatorWrite(), nil
+		}
+		return authz.OperatorRead(), nil
+	case ResourceQuery:
+		if write {
+			return authz.PreparedQueryWrite(segment), nil
+		}
+		return authz.PreparedQueryRead(segment), nil
+	case ResourceService:
+		if write {
+			return authz.ServiceWrite(segment, nil), nil
+		}
+		return authz.ServiceRead(segment), nil
+	case ResourceSession:
+		if write {
+			return authz.SessionWrite(segment), nil
+		}
+		return authz.SessionRead(segment), nil
+	default:
+		return false, fmt.Errorf("Invalid resource %q", resource)
+	}
+}
+
+// MatchingRule returns the rule that decides access to the segment of a
+// resource, formatted the way it would be written in a policy. When the
+// policies have no rule for it an empty string is returned and the decision
+// is left to the parent authorizer.
+func (p *PolicyAuthorizer) MatchingRule(resource, segment string) string {
+	var tree *radix.Tree
+	switch resource {
+	case ResourceACL:
+		return formatSimpleRule(resource, p.aclRule)
+	case ResourceKeyring:
+		return formatSimpleRule(resource, p.keyringRule)
+	case ResourceOperator:
+		return formatSimpleRule(resource, p.operatorRule)
+	case ResourceAgent:
+		tree = p.agentRules
+	case ResourceEvent:
+		tree = p.eventRules
+	case ResourceIntention:
+		tree = p.intentionRules
+	case ResourceKey:
+		tree = p.keyRules
+	case ResourceNode:
+		tree = p.nodeRules
+	case ResourceQuery:
+		tree = p.preparedQueryRules
+	case ResourceService:
+		tree = p.serviceRules
+	case ResourceSession:
+		tree = p.sessionRules
+	default:
+		return ""
+	}
+
+	rule, match, exact, ok := getPolicyMatch(segment, tree)
+	if !ok {
+		return ""
+	}
+
+	var policy string
+	switch r := rule.(type) {
+	case string:
+		policy = r
+	case RulePolicy:
+		policy = r.aclPolicy
+	}
+
+	// Intentions are granted within service rules.
+	name, field := resource, "policy"
+	if resource == ResourceIntention {
+		name, field = ResourceService, "intentions"
+	}
+	if !exact {
+		name += "_prefix"
+	}
+	return fmt.Sprintf("%s %q { %s = %q }", name, match, field, policy)
+}
+
+func formatSimpleRule(name, policy string) string {
+	if policy == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s = %q", name, policy)
+}
@@ -0,0 +1,79 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	rules := `
+acl = "read"
+operator = "write"
+key_prefix "" {
+	policy = "list"
+}
+key_prefix "foo/" {
+	policy = "write"
+}
+key "foo/private" {
+	policy = "deny"
+}
+service "web" {
+	policy = "write"
+}
+service_prefix "" {
+	policy = "read"
+	intentions = "write"
+}
+`
+	policy, err := NewPolicyFromSource("", 0, rules, SyntaxCurrent, nil)
+	require.NoError(t, err)
+	authz, err := NewPolicyAuthorizer(DenyAll(), []*Policy{policy}, nil)
+	require.NoError(t, err)
+
+	type testCase struct {
+		resource string
+		segment  string
+		access   string
+		allow    bool
+		rule     string
+	}
+
+	cases := []testCase{
+		{ResourceACL, "", PolicyRead, true, `acl = "read"`},
+		{ResourceACL, "", PolicyWrite, false, `acl = "read"`},
+		{ResourceOperator, "", PolicyWrite, true, `operator = "write"`},
+		{ResourceKeyring, "", PolicyRead, false, ""},
+		{ResourceKey, "bar", PolicyList, true, `key_prefix "" { policy = "list" }`},
+		{ResourceKey, "bar", PolicyRead, true, `key_prefix "" { policy = "list" }`},
+		{ResourceKey, "bar", PolicyWrite, false, `key_prefix "" { policy = "list" }`},
+		{ResourceKey, "foo/bar", PolicyWrite, true, `key_prefix "foo/" { policy = "write" }`},
+		{ResourceKey, "foo/private", PolicyRead, false, `key "foo/private" { policy = "deny" }`},
+		{ResourceService, "web", PolicyWrite, true, `service "web" { policy = "write" }`},
+		{ResourceService, "db", PolicyWrite, false, `service_prefix "" { policy = "read" }`},
+		{ResourceIntention, "web", PolicyRead, true, `service "web" { intentions = "read" }`},
+		{ResourceIntention, "db", PolicyWrite, true, `service_prefix "" { intentions = "write" }`},
+		{ResourceNode, "node1", PolicyRead, false, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.resource+"/"+tc.segment+"/"+tc.access, func(t *testing.T) {
+			allow, err := Check(authz, tc.resource, tc.segment, tc.access)
+			require.NoError(t, err)
+			require.Equal(t, tc.allow, allow)
+			require.Equal(t, tc.rule, authz.MatchingRule(tc.resource, tc.segment))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Check(authz, "bogus", "", PolicyRead)
+		require.Error(t, err)
+		_, err = Check(authz, ResourceKey, "", "bogus")
+		require.Error(t, err)
+		_, err = Check(authz, ResourceService, "", PolicyList)
+		require.Error(t, err)
+	})
+}
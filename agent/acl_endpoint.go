@@ -30,6 +30,20 @@ func (s *HTTPServer) checkACLDisabled(resp http.ResponseWriter, req *http.Reques
 	return true
 }
 
+// parseLabelFilter is used to parse the ?label=key:value query parameter used
+// for filtering ACL listings down to objects carrying all of the given labels.
+func parseLabelFilter(req *http.Request) map[string]string {
+	if filterList, ok := req.URL.Query()["label"]; ok {
+		filters := make(map[string]string)
+		for _, filter := range filterList {
+			key, value := ParseMetaPair(filter)
+			filters[key] = value
+		}
+		return filters
+	}
+	return nil
+}
+
 // ACLBootstrap is used to perform a one-time ACL bootstrap operation on
 // a cluster to get the first management token.
 func (s *HTTPServer) ACLBootstrap(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -177,6 +191,42 @@ func (s *HTTPServer) ACLRulesTranslateLegacyToken(resp http.ResponseWriter, req
 	return nil, nil
 }
 
+func (s *HTTPServer) ACLAuthorize(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled(resp, req) {
+		return nil, nil
+	}
+
+	var args structs.ACLAuthorizeRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	if args.Datacenter == "" {
+		args.Datacenter = s.agent.config.Datacenter
+	}
+
+	query := req.URL.Query()
+	args.AccessorID = query.Get("accessor")
+	args.Resource = query.Get("resource")
+	args.Segment = query.Get("segment")
+	args.Access = query.Get("access")
+
+	if args.Resource == "" {
+		return nil, BadRequestError{Reason: "Missing resource"}
+	}
+	if args.Access == "" {
+		return nil, BadRequestError{Reason: "Missing access level"}
+	}
+
+	var out structs.ACLAuthorizeResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC("ACL.Authorize", &args, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Authorization, nil
+}
+
 func (s *HTTPServer) ACLPolicyList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled(resp, req) {
 		return nil, nil
@@ -191,6 +241,8 @@ func (s *HTTPServer) ACLPolicyList(resp http.ResponseWriter, req *http.Request)
 		args.Datacenter = s.agent.config.Datacenter
 	}
 
+	args.Labels = parseLabelFilter(req)
+
 	var out structs.ACLPolicyListResponse
 	defer setMeta(resp, &out.QueryMeta)
 	if err := s.agent.RPC("ACL.PolicyList", &args, &out); err != nil {
@@ -260,6 +312,39 @@ func (s *HTTPServer) ACLPolicyRead(resp http.ResponseWriter, req *http.Request,
 	return out.Policy, nil
 }
 
+func (s *HTTPServer) ACLPolicyUsage(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled(resp, req) {
+		return nil, nil
+	}
+
+	args := structs.ACLPolicyGetRequest{
+		Datacenter: s.agent.config.Datacenter,
+		PolicyID:   strings.TrimPrefix(req.URL.Path, "/v1/acl/policy/usage/"),
+	}
+	if args.PolicyID == "" {
+		return nil, BadRequestError{Reason: "Missing policy ID"}
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	if args.Datacenter == "" {
+		args.Datacenter = s.agent.config.Datacenter
+	}
+
+	var out structs.ACLPolicyUsageResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC("ACL.PolicyUsage", &args, &out); err != nil {
+		return nil, err
+	}
+
+	if out.Usage == nil {
+		return nil, acl.ErrNotFound
+	}
+
+	return out.Usage, nil
+}
+
 func (s *HTTPServer) ACLPolicyCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled(resp, req) {
 		return nil, nil
@@ -268,22 +353,25 @@ func (s *HTTPServer) ACLPolicyCreate(resp http.ResponseWriter, req *http.Request
 	return s.ACLPolicyWrite(resp, req, "")
 }
 
-// fixCreateTimeAndHash is used to help in decoding the CreateTime and Hash
-// attributes from the ACL Token/Policy create/update requests. It is needed
-// to help mapstructure decode things properly when decodeBody is used.
+// fixCreateTimeAndHash is used to help in decoding the CreateTime,
+// ExpirationTime and Hash attributes from the ACL Token/Policy create/update
+// requests. It is needed to help mapstructure decode things properly when
+// decodeBody is used.
 func fixCreateTimeAndHash(raw interface{}) error {
 	rawMap, ok := raw.(map[string]interface{})
 	if !ok {
 		return nil
 	}
 
-	if val, ok := rawMap["CreateTime"]; ok {
-		if sval, ok := val.(string); ok {
-			t, err := time.Parse(time.RFC3339, sval)
-			if err != nil {
-				return err
+	for _, field := range []string{"CreateTime", "ExpirationTime"} {
+		if val, ok := rawMap[field]; ok {
+			if sval, ok := val.(string); ok {
+				t, err := time.Parse(time.RFC3339, sval)
+				if err != nil {
+					return err
+				}
+				rawMap[field] = t
 			}
-			rawMap["CreateTime"] = t
 		}
 	}
 
@@ -336,6 +424,157 @@ func (s *HTTPServer) ACLPolicyDelete(resp http.ResponseWriter, req *http.Request
 	return true, nil
 }
 
+func (s *HTTPServer) ACLRoleList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled(resp, req) {
+		return nil, nil
+	}
+
+	var args structs.ACLRoleListRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	if args.Datacenter == "" {
+		args.Datacenter = s.agent.config.Datacenter
+	}
+
+	args.Policy = req.URL.Query().Get("policy")
+	args.Labels = parseLabelFilter(req)
+
+	var out structs.ACLRoleListResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC("ACL.RoleList", &args, &out); err != nil {
+		return nil, err
+	}
+
+	// make sure we return an array and not nil
+	if out.Roles == nil {
+		out.Roles = make(structs.ACLRoles, 0)
+	}
+
+	return out.Roles, nil
+}
+
+func (s *HTTPServer) ACLRoleCRUD(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled(resp, req) {
+		return nil, nil
+	}
+
+	var fn func(resp http.ResponseWriter, req *http.Request, roleID string) (interface{}, error)
+
+	switch req.Method {
+	case "GET":
+		fn = s.ACLRoleReadByID
+
+	case "PUT":
+		fn = s.ACLRoleWrite
+
+	case "DELETE":
+		fn = s.ACLRoleDelete
+
+	default:
+		return nil, MethodNotAllowedError{req.Method, []string{"GET", "PUT", "DELETE"}}
+	}
+
+	roleID := strings.TrimPrefix(req.URL.Path, "/v1/acl/role/")
+	if roleID == "" && req.Method != "PUT" {
+		return nil, BadRequestError{Reason: "Missing role ID"}
+	}
+
+	return fn(resp, req, roleID)
+}
+
+func (s *HTTPServer) ACLRoleReadByName(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled(resp, req) {
+		return nil, nil
+	}
+
+	roleName := strings.TrimPrefix(req.URL.Path, "/v1/acl/role/name/")
+	if roleName == "" {
+		return nil, BadRequestError{Reason: "Missing role Name"}
+	}
+
+	return s.ACLRoleRead(resp, req, "", roleName)
+}
+
+func (s *HTTPServer) ACLRoleReadByID(resp http.ResponseWriter, req *http.Request, roleID string) (interface{}, error) {
+	return s.ACLRoleRead(resp, req, roleID, "")
+}
+
+func (s *HTTPServer) ACLRoleRead(resp http.ResponseWriter, req *http.Request, roleID, roleName string) (interface{}, error) {
+	args := structs.ACLRoleGetRequest{
+		Datacenter: s.agent.config.Datacenter,
+		RoleID:     roleID,
+		RoleName:   roleName,
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	if args.Datacenter == "" {
+		args.Datacenter = s.agent.config.Datacenter
+	}
+
+	var out structs.ACLRoleResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC("ACL.RoleRead", &args, &out); err != nil {
+		return nil, err
+	}
+
+	if out.Role == nil {
+		return nil, acl.ErrNotFound
+	}
+
+	return out.Role, nil
+}
+
+func (s *HTTPServer) ACLRoleCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled(resp, req) {
+		return nil, nil
+	}
+
+	return s.ACLRoleWrite(resp, req, "")
+}
+
+func (s *HTTPServer) ACLRoleWrite(resp http.ResponseWriter, req *http.Request, roleID string) (interface{}, error) {
+	args := structs.ACLRoleSetRequest{
+		Datacenter: s.agent.config.Datacenter,
+	}
+	s.parseToken(req, &args.Token)
+
+	if err := decodeBody(req, &args.Role, fixCreateTimeAndHash); err != nil {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Role decoding failed: %v", err)}
+	}
+
+	if args.Role.ID != "" && args.Role.ID != roleID {
+		return nil, BadRequestError{Reason: "Role ID in URL and payload do not match"}
+	} else if args.Role.ID == "" {
+		args.Role.ID = roleID
+	}
+
+	var out structs.ACLRole
+	if err := s.agent.RPC("ACL.RoleSet", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPServer) ACLRoleDelete(resp http.ResponseWriter, req *http.Request, roleID string) (interface{}, error) {
+	args := structs.ACLRoleDeleteRequest{
+		Datacenter: s.agent.config.Datacenter,
+		RoleID:     roleID,
+	}
+	s.parseToken(req, &args.Token)
+
+	var ignored string
+	if err := s.agent.RPC("ACL.RoleDelete", args, &ignored); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
 func (s *HTTPServer) ACLTokenList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled(resp, req) {
 		return nil, nil
@@ -354,6 +593,8 @@ func (s *HTTPServer) ACLTokenList(resp http.ResponseWriter, req *http.Request) (
 	}
 
 	args.Policy = req.URL.Query().Get("policy")
+	args.Role = req.URL.Query().Get("role")
+	args.Labels = parseLabelFilter(req)
 
 	var out structs.ACLTokenListResponse
 	defer setMeta(resp, &out.QueryMeta)
@@ -435,7 +676,7 @@ func (s *HTTPServer) ACLTokenCreate(resp http.ResponseWriter, req *http.Request)
 		return nil, nil
 	}
 
-	return s.ACLTokenSet(resp, req, "")
+	return s.aclTokenSetInternal(resp, req, "", true)
 }
 
 func (s *HTTPServer) ACLTokenGet(resp http.ResponseWriter, req *http.Request, tokenID string) (interface{}, error) {
@@ -467,8 +708,13 @@ func (s *HTTPServer) ACLTokenGet(resp http.ResponseWriter, req *http.Request, to
 }
 
 func (s *HTTPServer) ACLTokenSet(resp http.ResponseWriter, req *http.Request, tokenID string) (interface{}, error) {
+	return s.aclTokenSetInternal(resp, req, tokenID, false)
+}
+
+func (s *HTTPServer) aclTokenSetInternal(resp http.ResponseWriter, req *http.Request, tokenID string, create bool) (interface{}, error) {
 	args := structs.ACLTokenSetRequest{
 		Datacenter: s.agent.config.Datacenter,
+		Create:     create,
 	}
 	s.parseToken(req, &args.Token)
 
@@ -476,7 +722,8 @@ func (s *HTTPServer) ACLTokenSet(resp http.ResponseWriter, req *http.Request, to
 		return nil, BadRequestError{Reason: fmt.Sprintf("Token decoding failed: %v", err)}
 	}
 
-	if args.ACLToken.AccessorID != "" && args.ACLToken.AccessorID != tokenID {
+	// New tokens may be created with a chosen Accessor ID
+	if !create && args.ACLToken.AccessorID != "" && args.ACLToken.AccessorID != tokenID {
 		return nil, BadRequestError{Reason: "Token Accessor ID in URL and payload do not match"}
 	} else if args.ACLToken.AccessorID == "" {
 		args.ACLToken.AccessorID = tokenID
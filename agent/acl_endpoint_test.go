@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/stretchr/testify/require"
@@ -30,12 +32,18 @@ func TestACL_Disabled_Response(t *testing.T) {
 	tests := []testCase{
 		{"ACLBootstrap", a.srv.ACLBootstrap},
 		{"ACLReplicationStatus", a.srv.ACLReplicationStatus},
+		{"ACLAuthorize", a.srv.ACLAuthorize},
 		{"AgentToken", a.srv.AgentToken}, // See TestAgent_Token
 		{"ACLRulesTranslate", a.srv.ACLRulesTranslate},
 		{"ACLRulesTranslateLegacyToken", a.srv.ACLRulesTranslateLegacyToken},
 		{"ACLPolicyList", a.srv.ACLPolicyList},
 		{"ACLPolicyCRUD", a.srv.ACLPolicyCRUD},
 		{"ACLPolicyCreate", a.srv.ACLPolicyCreate},
+		{"ACLPolicyUsage", a.srv.ACLPolicyUsage},
+		{"ACLRoleList", a.srv.ACLRoleList},
+		{"ACLRoleCRUD", a.srv.ACLRoleCRUD},
+		{"ACLRoleCreate", a.srv.ACLRoleCreate},
+		{"ACLRoleReadByName", a.srv.ACLRoleReadByName},
 		{"ACLTokenList", a.srv.ACLTokenList},
 		{"ACLTokenCreate", a.srv.ACLTokenCreate},
 		{"ACLTokenSelf", a.srv.ACLTokenSelf},
@@ -355,6 +363,157 @@ func TestACL_HTTP(t *testing.T) {
 		})
 	})
 
+	t.Run("Role", func(t *testing.T) {
+		t.Run("Create", func(t *testing.T) {
+			roleInput := &structs.ACLRole{
+				Name:        "test",
+				Description: "test",
+				Policies: []structs.ACLRolePolicyLink{
+					structs.ACLRolePolicyLink{
+						Name: policyMap[idMap["policy-test"]].Name,
+					},
+				},
+			}
+
+			req, _ := http.NewRequest("PUT", "/v1/acl/role?token=root", jsonBody(roleInput))
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLRoleCreate(resp, req)
+			require.NoError(t, err)
+
+			role, ok := obj.(*structs.ACLRole)
+			require.True(t, ok)
+
+			// 36 = length of the string form of uuids
+			require.Len(t, role.ID, 36)
+			require.Equal(t, roleInput.Name, role.Name)
+			require.Equal(t, roleInput.Description, role.Description)
+			require.Len(t, role.Policies, 1)
+			require.Equal(t, idMap["policy-test"], role.Policies[0].ID)
+			require.True(t, role.CreateIndex > 0)
+			require.Equal(t, role.CreateIndex, role.ModifyIndex)
+			require.NotNil(t, role.Hash)
+			require.NotEqual(t, role.Hash, []byte{})
+
+			idMap["role-test"] = role.ID
+		})
+
+		t.Run("Update", func(t *testing.T) {
+			roleInput := &structs.ACLRole{
+				Name:        "test",
+				Description: "updated",
+				Policies: []structs.ACLRolePolicyLink{
+					structs.ACLRolePolicyLink{
+						ID: idMap["policy-read-all-nodes"],
+					},
+				},
+			}
+
+			req, _ := http.NewRequest("PUT", "/v1/acl/role/"+idMap["role-test"]+"?token=root", jsonBody(roleInput))
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLRoleCRUD(resp, req)
+			require.NoError(t, err)
+
+			role, ok := obj.(*structs.ACLRole)
+			require.True(t, ok)
+			require.Equal(t, idMap["role-test"], role.ID)
+			require.Equal(t, "updated", role.Description)
+			require.Len(t, role.Policies, 1)
+			require.Equal(t, idMap["policy-read-all-nodes"], role.Policies[0].ID)
+			require.True(t, role.ModifyIndex > role.CreateIndex)
+		})
+
+		t.Run("Update ID Mismatch", func(t *testing.T) {
+			roleInput := &structs.ACLRole{
+				ID:   "9f4d44f3-2ed5-4fca-9f0d-1e89a1e7c1a3",
+				Name: "test",
+			}
+
+			req, _ := http.NewRequest("PUT", "/v1/acl/role/"+idMap["role-test"]+"?token=root", jsonBody(roleInput))
+			resp := httptest.NewRecorder()
+			_, err := a.srv.ACLRoleCRUD(resp, req)
+			require.Error(t, err)
+			_, ok := err.(BadRequestError)
+			require.True(t, ok)
+		})
+
+		t.Run("Role CRUD Missing ID in URL", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/role/?token=root", nil)
+			resp := httptest.NewRecorder()
+			_, err := a.srv.ACLRoleCRUD(resp, req)
+			require.Error(t, err)
+			_, ok := err.(BadRequestError)
+			require.True(t, ok)
+		})
+
+		t.Run("Read", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/role/"+idMap["role-test"]+"?token=root", nil)
+			resp := httptest.NewRecorder()
+			raw, err := a.srv.ACLRoleCRUD(resp, req)
+			require.NoError(t, err)
+			role, ok := raw.(*structs.ACLRole)
+			require.True(t, ok)
+			require.Equal(t, "test", role.Name)
+		})
+
+		t.Run("Read by Name", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/role/name/test?token=root", nil)
+			resp := httptest.NewRecorder()
+			raw, err := a.srv.ACLRoleReadByName(resp, req)
+			require.NoError(t, err)
+			role, ok := raw.(*structs.ACLRole)
+			require.True(t, ok)
+			require.Equal(t, idMap["role-test"], role.ID)
+		})
+
+		t.Run("Read Not Found", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/role/name/not-found?token=root", nil)
+			resp := httptest.NewRecorder()
+			_, err := a.srv.ACLRoleReadByName(resp, req)
+			require.Error(t, err)
+			require.True(t, acl.IsErrNotFound(err))
+		})
+
+		t.Run("List", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/roles?token=root", nil)
+			resp := httptest.NewRecorder()
+			raw, err := a.srv.ACLRoleList(resp, req)
+			require.NoError(t, err)
+			roles, ok := raw.(structs.ACLRoles)
+			require.True(t, ok)
+			require.Len(t, roles, 1)
+			require.Equal(t, idMap["role-test"], roles[0].ID)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/roles?token=root&policy="+idMap["policy-test"], nil)
+			resp = httptest.NewRecorder()
+			raw, err = a.srv.ACLRoleList(resp, req)
+			require.NoError(t, err)
+			roles, ok = raw.(structs.ACLRoles)
+			require.True(t, ok)
+			require.Len(t, roles, 0)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			roleInput := &structs.ACLRole{
+				Name: "delete-me",
+			}
+			req, _ := http.NewRequest("PUT", "/v1/acl/role?token=root", jsonBody(roleInput))
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLRoleCreate(resp, req)
+			require.NoError(t, err)
+			role := obj.(*structs.ACLRole)
+
+			req, _ = http.NewRequest("DELETE", "/v1/acl/role/"+role.ID+"?token=root", nil)
+			resp = httptest.NewRecorder()
+			_, err = a.srv.ACLRoleCRUD(resp, req)
+			require.NoError(t, err)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/role/"+role.ID+"?token=root", nil)
+			resp = httptest.NewRecorder()
+			_, err = a.srv.ACLRoleCRUD(resp, req)
+			require.True(t, acl.IsErrNotFound(err))
+		})
+	})
+
 	t.Run("Token", func(t *testing.T) {
 		t.Run("Create", func(t *testing.T) {
 			tokenInput := &structs.ACLToken{
@@ -429,6 +588,41 @@ func TestACL_HTTP(t *testing.T) {
 			idMap["token-local"] = token.AccessorID
 			tokenMap[token.AccessorID] = token
 		})
+		t.Run("Create With Expiration", func(t *testing.T) {
+			tokenInput := map[string]interface{}{
+				"Description":   "expiring",
+				"ExpirationTTL": "1h",
+			}
+
+			req, _ := http.NewRequest("PUT", "/v1/acl/token?token=root", jsonBody(tokenInput))
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLTokenCreate(resp, req)
+			require.NoError(t, err)
+
+			token, ok := obj.(*structs.ACLToken)
+			require.True(t, ok)
+			require.NotNil(t, token.ExpirationTime)
+			require.True(t, token.ExpirationTime.Equal(token.CreateTime.Add(time.Hour)))
+
+			// echoing back the expiration time as a string is accepted
+			updateInput := map[string]interface{}{
+				"Description":    "still expiring",
+				"ExpirationTime": token.ExpirationTime.Format(time.RFC3339Nano),
+			}
+			req, _ = http.NewRequest("PUT", "/v1/acl/token/"+token.AccessorID+"?token=root", jsonBody(updateInput))
+			resp = httptest.NewRecorder()
+			obj, err = a.srv.ACLTokenCRUD(resp, req)
+			require.NoError(t, err)
+			updated, ok := obj.(*structs.ACLToken)
+			require.True(t, ok)
+			require.Equal(t, "still expiring", updated.Description)
+			require.True(t, updated.ExpirationTime.Equal(*token.ExpirationTime))
+
+			req, _ = http.NewRequest("DELETE", "/v1/acl/token/"+token.AccessorID+"?token=root", nil)
+			resp = httptest.NewRecorder()
+			_, err = a.srv.ACLTokenCRUD(resp, req)
+			require.NoError(t, err)
+		})
 		t.Run("Read", func(t *testing.T) {
 			expected := tokenMap[idMap["token-test"]]
 			req, _ := http.NewRequest("GET", "/v1/acl/token/"+expected.AccessorID+"?token=root", nil)
@@ -449,6 +643,32 @@ func TestACL_HTTP(t *testing.T) {
 			require.True(t, ok)
 			require.Equal(t, expected, token)
 		})
+		t.Run("Authorize", func(t *testing.T) {
+			accessor := idMap["token-test"]
+			req, _ := http.NewRequest("GET", "/v1/acl/authorize?token=root&accessor="+accessor+"&resource=node&segment=foo&access=read", nil)
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLAuthorize(resp, req)
+			require.NoError(t, err)
+			authz, ok := obj.(structs.ACLAuthorization)
+			require.True(t, ok)
+			require.True(t, authz.Allow)
+			require.Equal(t, `node_prefix "" { policy = "read" }`, authz.Rule)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/authorize?token=root&accessor="+accessor+"&resource=node&segment=foo&access=write", nil)
+			resp = httptest.NewRecorder()
+			obj, err = a.srv.ACLAuthorize(resp, req)
+			require.NoError(t, err)
+			authz, ok = obj.(structs.ACLAuthorization)
+			require.True(t, ok)
+			require.False(t, authz.Allow)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/authorize?token=root&resource=node", nil)
+			resp = httptest.NewRecorder()
+			_, err = a.srv.ACLAuthorize(resp, req)
+			require.Error(t, err)
+			_, ok = err.(BadRequestError)
+			require.True(t, ok)
+		})
 		t.Run("Clone", func(t *testing.T) {
 			tokenInput := &structs.ACLToken{
 				Description: "cloned token",
@@ -592,5 +812,92 @@ func TestACL_HTTP(t *testing.T) {
 			require.Len(t, token.Policies, 1)
 			require.Equal(t, structs.ACLPolicyGlobalManagementID, token.Policies[0].ID)
 		})
+		t.Run("List by Role", func(t *testing.T) {
+			tokenInput := &structs.ACLToken{
+				Description: "with role",
+				Roles: []structs.ACLTokenRoleLink{
+					structs.ACLTokenRoleLink{
+						Name: "test",
+					},
+				},
+			}
+			req, _ := http.NewRequest("PUT", "/v1/acl/token?token=root", jsonBody(tokenInput))
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLTokenCreate(resp, req)
+			require.NoError(t, err)
+			created, ok := obj.(*structs.ACLToken)
+			require.True(t, ok)
+			require.Len(t, created.Roles, 1)
+			require.Equal(t, idMap["role-test"], created.Roles[0].ID)
+			require.Equal(t, "test", created.Roles[0].Name)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/tokens?token=root&role="+idMap["role-test"], nil)
+			resp = httptest.NewRecorder()
+			raw, err := a.srv.ACLTokenList(resp, req)
+			require.NoError(t, err)
+			tokens, ok := raw.(structs.ACLTokenListStubs)
+			require.True(t, ok)
+			require.Len(t, tokens, 1)
+			require.Equal(t, created.AccessorID, tokens[0].AccessorID)
+			require.Equal(t, created.Roles, tokens[0].Roles)
+		})
+		t.Run("Policy Usage", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/policy/usage/"+structs.ACLPolicyGlobalManagementID+"?token=root", nil)
+			resp := httptest.NewRecorder()
+			raw, err := a.srv.ACLPolicyUsage(resp, req)
+			require.NoError(t, err)
+			usage, ok := raw.(*structs.ACLPolicyUsage)
+			require.True(t, ok)
+			require.Len(t, usage.Tokens, 1)
+			require.Equal(t, "Master Token", usage.Tokens[0].Description)
+			require.NotNil(t, usage.Roles)
+			require.Len(t, usage.Roles, 0)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/policy/usage/"+idMap["policy-read-all-nodes"]+"?token=root", nil)
+			resp = httptest.NewRecorder()
+			raw, err = a.srv.ACLPolicyUsage(resp, req)
+			require.NoError(t, err)
+			usage, ok = raw.(*structs.ACLPolicyUsage)
+			require.True(t, ok)
+			require.Len(t, usage.Roles, 1)
+			require.Equal(t, idMap["role-test"], usage.Roles[0].ID)
+		})
+		t.Run("Policy Usage Not Found", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/policy/usage/4ed4bb0e-ba2b-4e27-bd5c-2c5a0a9c8a11?token=root", nil)
+			resp := httptest.NewRecorder()
+			_, err := a.srv.ACLPolicyUsage(resp, req)
+			require.Equal(t, acl.ErrNotFound, err)
+		})
+		t.Run("List by Label", func(t *testing.T) {
+			tokenInput := &structs.ACLToken{
+				Description: "labeled",
+				Labels:      map[string]string{"team": "payments", "env": "prod"},
+			}
+			req, _ := http.NewRequest("PUT", "/v1/acl/token?token=root", jsonBody(tokenInput))
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLTokenCreate(resp, req)
+			require.NoError(t, err)
+			created, ok := obj.(*structs.ACLToken)
+			require.True(t, ok)
+			require.Equal(t, tokenInput.Labels, created.Labels)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/tokens?token=root&label=team:payments&label=env:prod", nil)
+			resp = httptest.NewRecorder()
+			raw, err := a.srv.ACLTokenList(resp, req)
+			require.NoError(t, err)
+			tokens, ok := raw.(structs.ACLTokenListStubs)
+			require.True(t, ok)
+			require.Len(t, tokens, 1)
+			require.Equal(t, created.AccessorID, tokens[0].AccessorID)
+			require.Equal(t, tokenInput.Labels, tokens[0].Labels)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/tokens?token=root&label=team:search", nil)
+			resp = httptest.NewRecorder()
+			raw, err = a.srv.ACLTokenList(resp, req)
+			require.NoError(t, err)
+			tokens, ok = raw.(structs.ACLTokenListStubs)
+			require.True(t, ok)
+			require.Len(t, tokens, 0)
+		})
 	})
 }
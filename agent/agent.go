@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -1008,6 +1009,20 @@ func (a *Agent) consulConfig() (*consul.Config, error) {
 	if a.config.ACLEnableKeyListPolicy {
 		base.ACLEnableKeyListPolicy = a.config.ACLEnableKeyListPolicy
 	}
+	if a.config.ACLPolicyNamePattern != "" {
+		re, err := regexp.Compile(a.config.ACLPolicyNamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid acl.name_patterns.policy: %v", err)
+		}
+		base.ACLPolicyNamePattern = re
+	}
+	if a.config.ACLRoleNamePattern != "" {
+		re, err := regexp.Compile(a.config.ACLRoleNamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid acl.name_patterns.role: %v", err)
+		}
+		base.ACLRoleNamePattern = re
+	}
 	if a.config.SessionTTLMin != 0 {
 		base.SessionTTLMin = a.config.SessionTTLMin
 	}
@@ -3525,6 +3540,11 @@ func (a *Agent) loadLimits(conf *config.RuntimeConfig) {
 	a.config.RPCMaxBurst = conf.RPCMaxBurst
 }
 
+func (a *Agent) loadACLCacheTTLs(conf *config.RuntimeConfig) {
+	a.config.ACLTokenTTL = conf.ACLTokenTTL
+	a.config.ACLPolicyTTL = conf.ACLPolicyTTL
+}
+
 func (a *Agent) ReloadConfig(newCfg *config.RuntimeConfig) error {
 	// Bulk update the services and checks
 	a.PauseSync()
@@ -3578,6 +3598,7 @@ func (a *Agent) ReloadConfig(newCfg *config.RuntimeConfig) error {
 	}
 
 	a.loadLimits(newCfg)
+	a.loadACLCacheTTLs(newCfg)
 
 	// create the config for the rpc server/client
 	consulCfg, err := a.consulConfig()
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/hashicorp/go-memdb"
@@ -36,6 +37,7 @@ import (
 	"github.com/hashicorp/serf/serf"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 )
 
 type Self struct {
@@ -122,11 +124,118 @@ func (s *HTTPServer) AgentMetrics(resp http.ResponseWriter, req *http.Request) (
 			ErrorHandling: promhttp.ContinueOnError,
 		}
 
-		handler := promhttp.HandlerFor(prometheus.DefaultGatherer, handlerOptions)
+		var gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+		if filter := s.metricsFilter(req); len(filter) > 0 {
+			gatherer = filterPrometheusGatherer(gatherer, filter)
+		}
+
+		handler := promhttp.HandlerFor(gatherer, handlerOptions)
 		handler.ServeHTTP(resp, req)
 		return nil, nil
 	}
-	return s.agent.MemSink.DisplayMetrics(resp, req)
+
+	out, err := s.agent.MemSink.DisplayMetrics(resp, req)
+	if err != nil {
+		return nil, err
+	}
+	if filter := s.metricsFilter(req); len(filter) > 0 {
+		if summary, ok := out.(metrics.MetricsSummary); ok {
+			return filterMetricsSummary(summary, filter), nil
+		}
+	}
+	return out, nil
+}
+
+// metricsFilterScopes lists the metrics that belong to a filter value but
+// are not named under it, by their prefix without the metrics_prefix.
+var metricsFilterScopes = map[string][]string{
+	"acl": {"leader.replication.acl"},
+}
+
+// metricsFilter returns the full dotted metric name prefixes requested with
+// the filter query parameter, for example "consul.acl" and
+// "consul.leader.replication.acl" for ?filter=acl. The parameter may be given
+// more than once. It returns nil if no filter was given.
+func (s *HTTPServer) metricsFilter(req *http.Request) []string {
+	var prefixes []string
+	for _, filter := range req.URL.Query()["filter"] {
+		filter = strings.Trim(filter, ".")
+		if filter == "" {
+			continue
+		}
+		prefixes = append(prefixes, filter)
+		prefixes = append(prefixes, metricsFilterScopes[filter]...)
+	}
+	if prefix := s.agent.config.Telemetry.MetricsPrefix; prefix != "" {
+		for i := range prefixes {
+			prefixes[i] = prefix + "." + prefixes[i]
+		}
+	}
+	return prefixes
+}
+
+// metricNameMatches reports whether name is one of the prefixes or falls
+// under one of them, using sep to separate the name segments.
+func metricNameMatches(name string, prefixes []string, sep string) bool {
+	for _, prefix := range prefixes {
+		if name == prefix || strings.HasPrefix(name, prefix+sep) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterMetricsSummary drops every metric from the summary whose name does
+// not fall under one of the given prefixes.
+func filterMetricsSummary(summary metrics.MetricsSummary, prefixes []string) metrics.MetricsSummary {
+	out := metrics.MetricsSummary{
+		Timestamp: summary.Timestamp,
+		Gauges:    make([]metrics.GaugeValue, 0),
+		Points:    make([]metrics.PointValue, 0),
+		Counters:  make([]metrics.SampledValue, 0),
+		Samples:   make([]metrics.SampledValue, 0),
+	}
+	for _, v := range summary.Gauges {
+		if metricNameMatches(v.Name, prefixes, ".") {
+			out.Gauges = append(out.Gauges, v)
+		}
+	}
+	for _, v := range summary.Points {
+		if metricNameMatches(v.Name, prefixes, ".") {
+			out.Points = append(out.Points, v)
+		}
+	}
+	for _, v := range summary.Counters {
+		if metricNameMatches(v.Name, prefixes, ".") {
+			out.Counters = append(out.Counters, v)
+		}
+	}
+	for _, v := range summary.Samples {
+		if metricNameMatches(v.Name, prefixes, ".") {
+			out.Samples = append(out.Samples, v)
+		}
+	}
+	return out
+}
+
+// filterPrometheusGatherer wraps a gatherer so that only the metric families
+// under one of the given dotted prefixes are exposed. Prometheus names use
+// underscores in place of the dots.
+func filterPrometheusGatherer(g prometheus.Gatherer, prefixes []string) prometheus.Gatherer {
+	names := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		names = append(names, strings.Replace(prefix, ".", "_", -1))
+	}
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		var out []*dto.MetricFamily
+		for _, family := range families {
+			if metricNameMatches(family.GetName(), names, "_") {
+				out = append(out, family)
+			}
+		}
+		return out, err
+	})
 }
 
 func (s *HTTPServer) AgentReload(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -16,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/config"
@@ -33,6 +34,7 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/copystructure"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1202,6 +1204,70 @@ func TestAgent_Metrics_ACLDeny(t *testing.T) {
 	})
 }
 
+func TestAgent_Metrics_filter(t *testing.T) {
+	t.Parallel()
+	a := NewTestAgent(t, t.Name(), "")
+	defer a.Shutdown()
+
+	prefix := a.config.Telemetry.MetricsPrefix
+	a.MemSink.SetGauge([]string{prefix, "acl", "cache", "entries"}, 1)
+	a.MemSink.IncrCounter([]string{prefix, "acl", "token", "cache_hit"}, 1)
+	a.MemSink.IncrCounter([]string{prefix, "aclx", "other"}, 1)
+	a.MemSink.AddSample([]string{prefix, "raft", "apply"}, 1)
+	a.MemSink.AddSample([]string{prefix, "leader", "replication", "acl", "token", "fetch"}, 1)
+	a.MemSink.AddSample([]string{prefix, "leader", "replication", "other"}, 1)
+
+	req, _ := http.NewRequest("GET", "/v1/agent/metrics?filter=acl", nil)
+	obj, err := a.srv.AgentMetrics(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	summary, ok := obj.(metrics.MetricsSummary)
+	require.True(t, ok)
+
+	require.Len(t, summary.Gauges, 1)
+	require.Equal(t, prefix+".acl.cache.entries", summary.Gauges[0].Name)
+	require.Len(t, summary.Counters, 1)
+	require.Equal(t, prefix+".acl.token.cache_hit", summary.Counters[0].Name)
+	// The ACL replication metrics are part of the acl filter.
+	require.Len(t, summary.Samples, 1)
+	require.Equal(t, prefix+".leader.replication.acl.token.fetch", summary.Samples[0].Name)
+
+	// The filter can be given more than once.
+	req, _ = http.NewRequest("GET", "/v1/agent/metrics?filter=acl&filter=raft", nil)
+	obj, err = a.srv.AgentMetrics(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	summary, ok = obj.(metrics.MetricsSummary)
+	require.True(t, ok)
+	require.Len(t, summary.Counters, 1)
+	require.Len(t, summary.Samples, 2)
+
+	// Without a filter everything is returned.
+	req, _ = http.NewRequest("GET", "/v1/agent/metrics", nil)
+	obj, err = a.srv.AgentMetrics(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	summary, ok = obj.(metrics.MetricsSummary)
+	require.True(t, ok)
+	require.Len(t, summary.Counters, 2)
+	require.Len(t, summary.Samples, 3)
+}
+
+func TestAgent_Metrics_filterPrometheus(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	for _, name := range []string{"consul_acl_resolve", "consul_aclx", "consul_raft_apply", "consul_acl", "consul_leader_replication_acl_token_fetch"} {
+		registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: name}))
+	}
+
+	families, err := filterPrometheusGatherer(registry, []string{"consul.acl", "consul.leader.replication.acl"}).Gather()
+	require.NoError(t, err)
+
+	var names []string
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	require.ElementsMatch(t, []string{"consul_acl", "consul_acl_resolve", "consul_leader_replication_acl_token_fetch"}, names)
+}
+
 func TestAgent_Reload(t *testing.T) {
 	t.Parallel()
 	dc1 := "dc1"
@@ -686,6 +686,8 @@ func (b *Builder) Build() (rt RuntimeConfig, err error) {
 		ACLReplicationToken:       b.stringValWithDefault(c.ACL.Tokens.Replication, b.stringVal(c.ACLReplicationToken)),
 		ACLTokenTTL:               b.durationValWithDefault("acl.token_ttl", c.ACL.TokenTTL, b.durationVal("acl_ttl", c.ACLTTL)),
 		ACLPolicyTTL:              b.durationVal("acl.policy_ttl", c.ACL.PolicyTTL),
+		ACLPolicyNamePattern:      b.stringVal(c.ACL.NamePatterns.Policy),
+		ACLRoleNamePattern:        b.stringVal(c.ACL.NamePatterns.Role),
 		ACLToken:                  b.stringValWithDefault(c.ACL.Tokens.Default, b.stringVal(c.ACLToken)),
 		ACLTokenReplication:       b.boolValWithDefault(c.ACL.TokenReplication, b.boolValWithDefault(c.EnableACLReplication, enableTokenReplication)),
 		ACLEnableTokenPersistence: b.boolValWithDefault(c.ACL.EnableTokenPersistence, false),
@@ -950,6 +952,16 @@ func (b *Builder) Validate(rt RuntimeConfig) error {
 	if rt.ACLDatacenter != "" && !reDatacenter.MatchString(rt.ACLDatacenter) {
 		return fmt.Errorf("acl_datacenter cannot be %q. Please use only [a-z0-9-_].", rt.ACLDatacenter)
 	}
+	if rt.ACLPolicyNamePattern != "" {
+		if _, err := regexp.Compile(rt.ACLPolicyNamePattern); err != nil {
+			return fmt.Errorf("acl.name_patterns.policy is not a valid regular expression: %v", err)
+		}
+	}
+	if rt.ACLRoleNamePattern != "" {
+		if _, err := regexp.Compile(rt.ACLRoleNamePattern); err != nil {
+			return fmt.Errorf("acl.name_patterns.role is not a valid regular expression: %v", err)
+		}
+	}
 	if rt.EnableUI && rt.UIDir != "" {
 		return fmt.Errorf(
 			"Both the ui and ui-dir flags were specified, please provide only one.\n" +
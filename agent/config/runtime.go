@@ -155,6 +155,21 @@ type RuntimeConfig struct {
 	// hcl: acl.token_ttl = "duration"
 	ACLPolicyTTL time.Duration
 
+	// ACLPolicyNamePattern is an optional regular expression that the name of
+	// every ACL policy created or updated through the API must match. It is
+	// enforced by the servers in the ACL datacenter. The builtin
+	// global-management policy is exempt.
+	//
+	// hcl: acl.name_patterns.policy = string
+	ACLPolicyNamePattern string
+
+	// ACLRoleNamePattern is an optional regular expression that the name of
+	// every ACL role created or updated through the API must match. It is
+	// enforced by the servers in the ACL datacenter.
+	//
+	// hcl: acl.name_patterns.role = string
+	ACLRoleNamePattern string
+
 	// ACLToken is the default token used to make requests if a per-request
 	// token is not provided. If not configured the 'anonymous' token is used.
 	//
@@ -1385,6 +1385,24 @@ func TestConfigFlagsAndEdgecases(t *testing.T) {
 			},
 			warns: []string{`The 'acl_datacenter' field is deprecated. Use the 'primary_datacenter' field instead.`},
 		},
+		{
+			desc: "acl.name_patterns.policy invalid",
+			args: []string{
+				`-data-dir=` + dataDir,
+			},
+			json: []string{`{ "acl": { "name_patterns": { "policy": "team-(" } } }`},
+			hcl:  []string{`acl = { name_patterns = { policy = "team-(" } }`},
+			err:  "acl.name_patterns.policy is not a valid regular expression: error parsing regexp: missing closing ): `team-(`",
+		},
+		{
+			desc: "acl.name_patterns.role invalid",
+			args: []string{
+				`-data-dir=` + dataDir,
+			},
+			json: []string{`{ "acl": { "name_patterns": { "role": "team-(" } } }`},
+			hcl:  []string{`acl = { name_patterns = { role = "team-(" } }`},
+			err:  "acl.name_patterns.role is not a valid regular expression: error parsing regexp: missing closing ): `team-(`",
+		},
 		{
 			desc: "acl_replication_token enables acl replication",
 			args: []string{`-data-dir=` + dataDir},
@@ -2903,6 +2921,10 @@ func TestFullConfig(t *testing.T) {
 				"policy_ttl": "1123s",
 				"token_ttl": "3321s",
 				"enable_token_replication" : true,
+				"name_patterns" : {
+					"policy" : "^pol-[a-z]+$",
+					"role" : "^role-[a-z]+$"
+				},
 				"tokens" : {
 					"master" : "8a19ac27",
 					"agent_master" : "64fd0e08",
@@ -3455,6 +3477,10 @@ func TestFullConfig(t *testing.T) {
 				policy_ttl = "1123s"
 				token_ttl = "3321s"
 				enable_token_replication = true
+				name_patterns = {
+					policy = "^pol-[a-z]+$"
+					role = "^role-[a-z]+$"
+				}
 				tokens = {
 					master = "8a19ac27",
 					agent_master = "64fd0e08",
@@ -4127,6 +4153,8 @@ func TestFullConfig(t *testing.T) {
 		ACLReplicationToken:              "5795983a",
 		ACLTokenTTL:                      3321 * time.Second,
 		ACLPolicyTTL:                     1123 * time.Second,
+		ACLPolicyNamePattern:             "^pol-[a-z]+$",
+		ACLRoleNamePattern:               "^role-[a-z]+$",
 		ACLToken:                         "418fdff1",
 		ACLTokenReplication:              true,
 		AdvertiseAddrLAN:                 ipAddr("17.99.29.16"),
@@ -4943,6 +4971,8 @@ func TestSanitize(t *testing.T) {
 		"ACLEnableTokenPersistence": false,
 		"ACLEnforceVersion8": false,
 		"ACLMasterToken": "hidden",
+		"ACLPolicyNamePattern": "",
+		"ACLRoleNamePattern": "",
 		"ACLPolicyTTL": "0s",
 		"ACLReplicationToken": "hidden",
 		"ACLTokenReplication": false,
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/sentinel"
 	"golang.org/x/sync/singleflight"
 	"golang.org/x/time/rate"
@@ -85,6 +86,7 @@ type ACLResolverDelegate interface {
 	UseLegacyACLs() bool
 	ResolveIdentityFromToken(token string) (bool, structs.ACLIdentity, error)
 	ResolvePolicyFromID(policyID string) (bool, *structs.ACLPolicy, error)
+	ResolveRoleFromID(roleID string) (bool, *structs.ACLRole, error)
 	RPC(method string, args interface{}, reply interface{}) error
 }
 
@@ -121,9 +123,11 @@ type ACLResolverConfig struct {
 // Supports:
 //   - Resolving tokens locally via the ACLResolverDelegate
 //   - Resolving policies locally via the ACLResolverDelegate
+//   - Resolving roles locally via the ACLResolverDelegate
 //   - Resolving legacy tokens remotely via a ACL.GetPolicy RPC
 //   - Resolving tokens remotely via an ACL.TokenRead RPC
 //   - Resolving policies remotely via an ACL.PolicyResolve RPC
+//   - Resolving roles remotely via an ACL.RoleResolve RPC
 //
 // Remote Resolution:
 //   Remote resolution can be done syncrhonously or asynchronously depending
@@ -149,10 +153,18 @@ type ACLResolver struct {
 	cache         *structs.ACLCaches
 	identityGroup singleflight.Group
 	policyGroup   singleflight.Group
+	roleGroup     singleflight.Group
 	legacyGroup   singleflight.Group
 
 	down acl.Authorizer
 
+	// tokenTTL and policyTTL start out as the configured cache TTLs but
+	// may be changed by a config reload, so they are guarded by ttlLock
+	// rather than read from config.
+	tokenTTL  time.Duration
+	policyTTL time.Duration
+	ttlLock   sync.RWMutex
+
 	autoDisable  bool
 	disabled     time.Time
 	disabledLock sync.RWMutex
@@ -200,16 +212,34 @@ func NewACLResolver(config *ACLResolverConfig) (*ACLResolver, error) {
 		cache:       cache,
 		autoDisable: config.AutoDisable,
 		down:        down,
+		tokenTTL:    config.Config.ACLTokenTTL,
+		policyTTL:   config.Config.ACLPolicyTTL,
 	}, nil
 }
 
+// cacheTTLs returns the current token and policy cache TTLs.
+func (r *ACLResolver) cacheTTLs() (time.Duration, time.Duration) {
+	r.ttlLock.RLock()
+	defer r.ttlLock.RUnlock()
+	return r.tokenTTL, r.policyTTL
+}
+
+// reloadCacheTTLs updates the token and policy cache TTLs. Entries already
+// in the cache are judged against the new TTLs from now on.
+func (r *ACLResolver) reloadCacheTTLs(tokenTTL, policyTTL time.Duration) {
+	r.ttlLock.Lock()
+	defer r.ttlLock.Unlock()
+	r.tokenTTL = tokenTTL
+	r.policyTTL = policyTTL
+}
+
 func (r *ACLResolver) fetchAndCacheTokenLegacy(token string, cached *structs.AuthorizerCacheEntry) (acl.Authorizer, error) {
 	req := structs.ACLPolicyResolveLegacyRequest{
 		Datacenter: r.delegate.ACLDatacenter(true),
 		ACL:        token,
 	}
 
-	cacheTTL := r.config.ACLTokenTTL
+	cacheTTL, _ := r.cacheTTLs()
 	if cached != nil {
 		cacheTTL = cached.TTL
 	}
@@ -285,7 +315,8 @@ func (r *ACLResolver) resolveTokenLegacy(token string) (acl.Authorizer, error) {
 	// Look in the cache prior to making a RPC request
 	entry := r.cache.GetAuthorizer(token)
 
-	if entry != nil && entry.Age() <= minTTL(entry.TTL, r.config.ACLTokenTTL) {
+	tokenTTL, _ := r.cacheTTLs()
+	if entry != nil && entry.Age() <= minTTL(entry.TTL, tokenTTL) {
 		metrics.IncrCounter([]string{"acl", "token", "cache_hit"}, 1)
 		if entry.Authorizer != nil {
 			return entry.Authorizer, nil
@@ -370,7 +401,8 @@ func (r *ACLResolver) resolveIdentityFromToken(token string) (structs.ACLIdentit
 
 	// Check the cache before making any RPC requests
 	cacheEntry := r.cache.GetIdentity(token)
-	if cacheEntry != nil && cacheEntry.Age() <= r.config.ACLTokenTTL {
+	tokenTTL, _ := r.cacheTTLs()
+	if cacheEntry != nil && cacheEntry.Age() <= tokenTTL {
 		metrics.IncrCounter([]string{"acl", "token", "cache_hit"}, 1)
 		return cacheEntry.Identity, nil
 	}
@@ -475,6 +507,171 @@ func (r *ACLResolver) fetchAndCachePoliciesForIdentity(identity structs.ACLIdent
 	return out, nil
 }
 
+func (r *ACLResolver) fetchAndCacheRolesForIdentity(identity structs.ACLIdentity, roleIDs []string, cached map[string]*structs.RoleCacheEntry) (map[string]*structs.ACLRole, error) {
+	req := structs.ACLRoleBatchGetRequest{
+		Datacenter: r.delegate.ACLDatacenter(false),
+		RoleIDs:    roleIDs,
+		QueryOptions: structs.QueryOptions{
+			Token:      identity.SecretToken(),
+			AllowStale: true,
+		},
+	}
+
+	var resp structs.ACLRoleBatchResponse
+	err := r.delegate.RPC("ACL.RoleResolve", &req, &resp)
+	if err == nil {
+		out := make(map[string]*structs.ACLRole)
+		for _, role := range resp.Roles {
+			out[role.ID] = role
+		}
+
+		for _, roleID := range roleIDs {
+			if role, ok := out[roleID]; ok {
+				r.cache.PutRole(roleID, role)
+			} else {
+				r.cache.PutRole(roleID, nil)
+			}
+		}
+		return out, nil
+	}
+
+	if acl.IsErrNotFound(err) {
+		// make sure to indicate that this identity is no longer valid within
+		// the cache
+		r.cache.PutIdentity(identity.SecretToken(), nil)
+
+		// Do not touch the role cache. Getting a top level ACL not found error
+		// only indicates that the secret token used in the request
+		// no longer exists
+		return nil, &policyTokenError{acl.ErrNotFound, identity.SecretToken()}
+	}
+
+	if acl.IsErrPermissionDenied(err) {
+		// invalidate our ID cache so that identity resolution will take place
+		// again in the future
+		r.cache.RemoveIdentity(identity.SecretToken())
+
+		// Do not remove from the role cache for permission denied
+		// what this does indicate is that our view of the token is out of date
+		return nil, &policyTokenError{acl.ErrPermissionDenied, identity.SecretToken()}
+	}
+
+	// other RPC error - use cache if available
+
+	extendCache := r.config.ACLDownPolicy == "extend-cache" || r.config.ACLDownPolicy == "async-cache"
+
+	out := make(map[string]*structs.ACLRole)
+	insufficientCache := false
+	for _, roleID := range roleIDs {
+		if entry, ok := cached[roleID]; extendCache && ok {
+			r.cache.PutRole(roleID, entry.Role)
+			if entry.Role != nil {
+				out[roleID] = entry.Role
+			}
+		} else {
+			r.cache.PutRole(roleID, nil)
+			insufficientCache = true
+		}
+	}
+	if insufficientCache {
+		return nil, ACLRemoteError{Err: err}
+	}
+	return out, nil
+}
+
+// resolveRolesForIdentity resolves the roles linked to the identity. Roles
+// are cached for the same duration as policies.
+func (r *ACLResolver) resolveRolesForIdentity(identity structs.ACLIdentity) (structs.ACLRoles, error) {
+	roleIDs := identity.RoleIDs()
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+
+	// Roles are replicated along with policies so servers only attempt to
+	// resolve them locally.
+	roles := make(structs.ACLRoles, 0, len(roleIDs))
+
+	var missing []string
+	var expired []*structs.ACLRole
+	expCacheMap := make(map[string]*structs.RoleCacheEntry)
+	_, roleTTL := r.cacheTTLs()
+
+	for _, roleID := range roleIDs {
+		if done, role, err := r.delegate.ResolveRoleFromID(roleID); done {
+			if err != nil && !acl.IsErrNotFound(err) {
+				return nil, err
+			}
+
+			if role != nil {
+				roles = append(roles, role)
+			} else {
+				r.logger.Printf("[WARN] acl: role %q not found for identity %q", roleID, identity.ID())
+			}
+
+			continue
+		}
+
+		// create the missing list which we can execute an RPC to get all the missing roles at once
+		entry := r.cache.GetRole(roleID)
+		if entry == nil {
+			missing = append(missing, roleID)
+			continue
+		}
+
+		if entry.Role == nil {
+			// this happens when we cache a negative response for the roles existence
+			continue
+		}
+
+		if entry.Age() >= roleTTL {
+			expired = append(expired, entry.Role)
+			expCacheMap[roleID] = entry
+		} else {
+			roles = append(roles, entry.Role)
+		}
+	}
+
+	// Hot-path if we have no missing or expired roles
+	if len(missing)+len(expired) == 0 {
+		return roles, nil
+	}
+
+	hasMissing := len(missing) > 0
+
+	fetchIDs := missing
+	for _, role := range expired {
+		fetchIDs = append(fetchIDs, role.ID)
+	}
+
+	// Background a RPC request and wait on it if we must
+	waitChan := r.roleGroup.DoChan(identity.SecretToken(), func() (interface{}, error) {
+		roles, err := r.fetchAndCacheRolesForIdentity(identity, fetchIDs, expCacheMap)
+		return roles, err
+	})
+
+	waitForResult := hasMissing || r.config.ACLDownPolicy != "async-cache"
+	if !waitForResult {
+		// waitForResult being false requires that all the roles were cached already
+		return append(roles, expired...), nil
+	}
+
+	res := <-waitChan
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	if res.Val != nil {
+		foundRoles := res.Val.(map[string]*structs.ACLRole)
+
+		for _, role := range foundRoles {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
 func (r *ACLResolver) filterPoliciesByScope(policies structs.ACLPolicies) structs.ACLPolicies {
 	var out structs.ACLPolicies
 	for _, policy := range policies {
@@ -495,8 +692,32 @@ func (r *ACLResolver) filterPoliciesByScope(policies structs.ACLPolicies) struct
 }
 
 func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (structs.ACLPolicies, error) {
+	roles, err := r.resolveRolesForIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+
 	policyIDs := identity.PolicyIDs()
-	if len(policyIDs) == 0 {
+	serviceIdentities := identity.ServiceIdentityList()
+	if len(roles) > 0 {
+		// Policies linked through roles are merged with the directly linked
+		// ones. The same policy may be linked more than once.
+		seen := make(map[string]struct{})
+		for _, id := range policyIDs {
+			seen[id] = struct{}{}
+		}
+		for _, role := range roles {
+			for _, id := range role.PolicyIDs() {
+				if _, ok := seen[id]; !ok {
+					seen[id] = struct{}{}
+					policyIDs = append(policyIDs, id)
+				}
+			}
+			serviceIdentities = append(serviceIdentities, role.ServiceIdentityList()...)
+		}
+	}
+
+	if len(policyIDs) == 0 && len(serviceIdentities) == 0 {
 		policy := identity.EmbeddedPolicy()
 		if policy != nil {
 			return []*structs.ACLPolicy{policy}, nil
@@ -506,14 +727,21 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 		return nil, nil
 	}
 
+	// Service identities are turned into synthetic policies which are never
+	// stored so they do not need to be fetched.
+	policies := make([]*structs.ACLPolicy, 0, len(policyIDs)+len(serviceIdentities))
+	for _, s := range dedupeServiceIdentities(serviceIdentities) {
+		policies = append(policies, s.SyntheticPolicy())
+	}
+
 	// For the new ACLs policy replication is mandatory for correct operation on servers. Therefore
 	// we only attempt to resolve policies locally
-	policies := make([]*structs.ACLPolicy, 0, len(policyIDs))
 
 	// Get all associated policies
 	var missing []string
 	var expired []*structs.ACLPolicy
 	expCacheMap := make(map[string]*structs.PolicyCacheEntry)
+	_, policyTTL := r.cacheTTLs()
 
 	for _, policyID := range policyIDs {
 		if done, policy, err := r.delegate.ResolvePolicyFromID(policyID); done {
@@ -542,7 +770,7 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 			continue
 		}
 
-		if entry.Age() >= r.config.ACLPolicyTTL {
+		if entry.Age() >= policyTTL {
 			expired = append(expired, entry.Policy)
 			expCacheMap[policyID] = entry
 		} else {
@@ -592,6 +820,42 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 	return r.filterPoliciesByScope(policies), nil
 }
 
+// dedupeServiceIdentities merges service identities for the same service so
+// that each service yields a single synthetic policy. If any of the merged
+// identities is valid in all datacenters then so is the result.
+func dedupeServiceIdentities(in []*structs.ACLServiceIdentity) []*structs.ACLServiceIdentity {
+	if len(in) <= 1 {
+		return in
+	}
+
+	var out []*structs.ACLServiceIdentity
+	byName := make(map[string]*structs.ACLServiceIdentity)
+	for _, s := range in {
+		existing, ok := byName[s.ServiceName]
+		if !ok {
+			s = s.Clone()
+			byName[s.ServiceName] = s
+			out = append(out, s)
+			continue
+		}
+
+		if len(existing.Datacenters) == 0 {
+			continue
+		}
+		if len(s.Datacenters) == 0 {
+			existing.Datacenters = nil
+			continue
+		}
+		for _, dc := range s.Datacenters {
+			if !lib.StrContains(existing.Datacenters, dc) {
+				existing.Datacenters = append(existing.Datacenters, dc)
+			}
+		}
+	}
+
+	return out
+}
+
 func (r *ACLResolver) resolveTokenToPolicies(token string) (structs.ACLPolicies, error) {
 	_, policies, err := r.resolveTokenToIdentityAndPolicies(token)
 	return policies, err
@@ -608,6 +872,9 @@ func (r *ACLResolver) resolveTokenToIdentityAndPolicies(token string) (structs.A
 			return nil, nil, err
 		} else if identity == nil {
 			return nil, nil, acl.ErrNotFound
+		} else if identity.IsExpired(time.Now()) {
+			// cached identities may outlive their expiration time
+			return nil, nil, acl.ErrNotFound
 		}
 
 		lastIdentity = identity
@@ -1012,6 +1279,10 @@ func (f *aclFilter) filterPreparedQueries(queries *structs.PreparedQueries) {
 	*queries = ret
 }
 
+// redactTokenSecret replaces every secret field of the token with a
+// placeholder unless the caller has acl:write. This is the only place that
+// knows which token fields are secret, so any new secret field must be
+// redacted here.
 func (f *aclFilter) redactTokenSecret(token **structs.ACLToken) {
 	if token == nil || *token == nil || f == nil || f.authorizer.ACLWrite() {
 		return
@@ -1031,6 +1302,24 @@ func (f *aclFilter) redactTokenSecrets(tokens *structs.ACLTokens) {
 	*tokens = ret
 }
 
+// redactTokenResponse redacts the token in an RPC response and records
+// whether that happened so clients can tell a placeholder from a secret.
+func (f *aclFilter) redactTokenResponse(resp *structs.ACLTokenResponse) {
+	if resp.Token == nil {
+		return
+	}
+	f.redactTokenSecret(&resp.Token)
+	resp.Redacted = !f.authorizer.ACLWrite()
+}
+
+// redactTokenBatchResponse is the batch equivalent of redactTokenResponse.
+func (f *aclFilter) redactTokenBatchResponse(resp *structs.ACLTokenBatchResponse) {
+	tokens := structs.ACLTokens(resp.Tokens)
+	f.redactTokenSecrets(&tokens)
+	resp.Tokens = tokens
+	resp.Redacted = !f.authorizer.ACLWrite()
+}
+
 func (r *ACLResolver) filterACLWithAuthorizer(authorizer acl.Authorizer, subj interface{}) error {
 	if authorizer == nil {
 		return nil
@@ -1084,6 +1373,12 @@ func (r *ACLResolver) filterACLWithAuthorizer(authorizer acl.Authorizer, subj in
 	case **structs.ACLToken:
 		filt.redactTokenSecret(v)
 
+	case *structs.ACLTokenResponse:
+		filt.redactTokenResponse(v)
+
+	case *structs.ACLTokenBatchResponse:
+		filt.redactTokenBatchResponse(v)
+
 	default:
 		panic(fmt.Errorf("Unhandled type passed to ACL filter: %#v", subj))
 	}
@@ -25,6 +25,14 @@ var clientACLCacheConfig *structs.ACLCachesConfig = &structs.ACLCachesConfig{
 	ParsedPolicies: 128,
 	// Authorizers - number of compiled multi-policy effective policies that can be cached
 	Authorizers: 256,
+	// Roles - number of ACL roles that can be cached
+	Roles: 128,
+	// The byte limits bound the estimated memory used by each cache so that
+	// large tokens and policies can't grow a client agent without bound.
+	IdentitiesBytes:     4 * 1024 * 1024,
+	PoliciesBytes:       4 * 1024 * 1024,
+	ParsedPoliciesBytes: 4 * 1024 * 1024,
+	RolesBytes:          4 * 1024 * 1024,
 }
 
 func (c *Client) UseLegacyACLs() bool {
@@ -96,6 +104,11 @@ func (c *Client) ResolvePolicyFromID(policyID string) (bool, *structs.ACLPolicy,
 	return false, nil, nil
 }
 
+func (c *Client) ResolveRoleFromID(roleID string) (bool, *structs.ACLRole, error) {
+	// clients do no local role resolution at the moment
+	return false, nil, nil
+}
+
 func (c *Client) ResolveToken(token string) (acl.Authorizer, error) {
 	return c.acls.ResolveToken(token)
 }
@@ -19,12 +19,48 @@ import (
 
 const (
 	// aclBootstrapReset is the file name to create in the data dir. It's only contents
-	// should be the reset index
+	// should be the reset index. "consul acl bootstrap -reset-index" writes it
+	// under the same name, see TestBootstrapCommand_reset.
 	aclBootstrapReset = "acl-bootstrap-reset"
+
+	// aclTokenMinExpirationTTL is the shortest lifetime a token may be
+	// created with. Expired tokens are only reaped periodically so much
+	// shorter lifetimes would not be meaningful.
+	aclTokenMinExpirationTTL = time.Minute
 )
 
 // Regex for matching
 var validPolicyName = regexp.MustCompile(`^[A-Za-z0-9\-_]{1,128}$`)
+var validRoleName = regexp.MustCompile(`^[A-Za-z0-9\-_]{1,256}$`)
+var validServiceIdentityName = regexp.MustCompile(`^[a-z0-9]([a-z0-9\-_]*[a-z0-9])?$`)
+
+// isValidServiceIdentityName returns true if the provided name can be used as
+// an ACLServiceIdentity ServiceName. This is more restrictive than standard
+// catalog registration, which basically takes the view that "everything is
+// valid".
+func isValidServiceIdentityName(name string) bool {
+	if len(name) < 1 || len(name) > 256 {
+		return false
+	}
+	return validServiceIdentityName.MatchString(name)
+}
+
+// validateServiceIdentities checks the service identities of a token or role
+// and returns them with identities for the same service merged.
+func validateServiceIdentities(identities []*structs.ACLServiceIdentity, local bool) ([]*structs.ACLServiceIdentity, error) {
+	for _, s := range identities {
+		if s == nil || s.ServiceName == "" {
+			return nil, fmt.Errorf("Service identity is missing the service name field")
+		}
+		if !isValidServiceIdentityName(s.ServiceName) {
+			return nil, fmt.Errorf("Service identity %q has an invalid name. Only lowercase alphanumeric characters, '-' and '_' are allowed", s.ServiceName)
+		}
+		if local && len(s.Datacenters) > 0 {
+			return nil, fmt.Errorf("Service identity %q cannot specify a list of datacenters on a local token", s.ServiceName)
+		}
+	}
+	return dedupeServiceIdentities(identities), nil
+}
 
 // ACL endpoint is used to manipulate ACLs
 type ACL struct {
@@ -211,12 +247,6 @@ func (a *ACL) TokenRead(args *structs.ACLTokenGetRequest, reply *structs.ACLToke
 
 			if args.TokenIDType == structs.ACLTokenAccessor {
 				index, token, err = state.ACLTokenGetByAccessor(ws, args.TokenID)
-				if token != nil {
-					a.srv.filterACLWithAuthorizer(rule, &token)
-					if !rule.ACLWrite() {
-						reply.Redacted = true
-					}
-				}
 			} else {
 				index, token, err = state.ACLTokenGetBySecret(ws, args.TokenID)
 			}
@@ -225,7 +255,19 @@ func (a *ACL) TokenRead(args *structs.ACLTokenGetRequest, reply *structs.ACLToke
 				return err
 			}
 
+			// Expired tokens are treated as already deleted even before
+			// the leader gets around to reaping them.
+			if token != nil && token.IsExpired(time.Now()) {
+				token = nil
+			}
+
 			reply.Index, reply.Token = index, token
+
+			// Lookups by secret are made by callers that already hold the
+			// secret, so there is nothing to redact for them.
+			if args.TokenIDType == structs.ACLTokenAccessor {
+				a.srv.filterACLWithAuthorizer(rule, reply)
+			}
 			return nil
 		})
 }
@@ -256,7 +298,7 @@ func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLTok
 	_, token, err := a.srv.fsm.State().ACLTokenGetByAccessor(nil, args.ACLToken.AccessorID)
 	if err != nil {
 		return err
-	} else if token == nil {
+	} else if token == nil || token.IsExpired(time.Now()) {
 		return acl.ErrNotFound
 	} else if !a.srv.InACLDatacenter() && !token.Local {
 		// global token writes must be forwarded to the primary DC
@@ -271,9 +313,13 @@ func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLTok
 	cloneReq := structs.ACLTokenSetRequest{
 		Datacenter: args.Datacenter,
 		ACLToken: structs.ACLToken{
-			Policies:    token.Policies,
-			Local:       token.Local,
-			Description: token.Description,
+			Policies:          token.Policies,
+			Roles:             token.Roles,
+			ServiceIdentities: token.ServiceIdentities,
+			Local:             token.Local,
+			Description:       token.Description,
+			ExpirationTime:    token.ExpirationTime,
+			Labels:            token.Labels,
 		},
 		WriteRequest: args.WriteRequest,
 	}
@@ -325,23 +371,73 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 
 	state := a.srv.fsm.State()
 
-	if token.AccessorID == "" {
+	if token.AccessorID == "" || args.Create {
 		// Token Create
 		var err error
 
-		// Generate the AccessorID
-		token.AccessorID, err = lib.GenerateUUID(a.srv.checkTokenUUID)
-		if err != nil {
-			return err
+		// Generate the AccessorID unless one was chosen, such as when
+		// tokens are migrated from another cluster
+		if token.AccessorID == "" {
+			token.AccessorID, err = lib.GenerateUUID(a.srv.checkTokenUUID)
+			if err != nil {
+				return err
+			}
+		} else {
+			if _, err := uuid.ParseUUID(token.AccessorID); err != nil {
+				return fmt.Errorf("Invalid Token: AccessorID is not a valid UUID")
+			}
+			if structs.ACLIDReserved(token.AccessorID) {
+				return fmt.Errorf("Invalid Token: UUIDs with the prefix %q are reserved", structs.ACLReservedPrefix)
+			}
+			if _, existing, err := state.ACLTokenGetByAccessor(nil, token.AccessorID); err != nil {
+				return fmt.Errorf("Failed to lookup the acl token %q: %v", token.AccessorID, err)
+			} else if existing != nil {
+				return fmt.Errorf("Invalid Token: AccessorID is already in use")
+			}
 		}
 
-		// Generate the SecretID - not supporting non-UUID secrets
-		token.SecretID, err = lib.GenerateUUID(a.srv.checkTokenUUID)
-		if err != nil {
-			return err
+		// Generate the SecretID unless one was chosen - not supporting
+		// non-UUID secrets
+		if token.SecretID == "" {
+			token.SecretID, err = lib.GenerateUUID(a.srv.checkTokenUUID)
+			if err != nil {
+				return err
+			}
+		} else {
+			if _, err := uuid.ParseUUID(token.SecretID); err != nil {
+				return fmt.Errorf("Invalid Token: SecretID is not a valid UUID")
+			}
+			if structs.ACLIDReserved(token.SecretID) {
+				return fmt.Errorf("Invalid Token: UUIDs with the prefix %q are reserved", structs.ACLReservedPrefix)
+			}
+			if _, existing, err := state.ACLTokenGetBySecret(nil, token.SecretID); err != nil {
+				return fmt.Errorf("Failed to lookup the acl token: %v", err)
+			} else if existing != nil {
+				return fmt.Errorf("Invalid Token: SecretID is already in use")
+			}
 		}
 
 		token.CreateTime = time.Now()
+
+		if token.ExpirationTTL < 0 {
+			return fmt.Errorf("Token Expiration TTL '%s' should be > 0", token.ExpirationTTL)
+		}
+		if token.HasExpirationTime() && token.ExpirationTTL != 0 {
+			return fmt.Errorf("Token Expiration TTL and Expiration Time cannot both be set")
+		}
+		if token.ExpirationTTL != 0 {
+			expirationTime := token.CreateTime.Add(token.ExpirationTTL)
+			token.ExpirationTime = &expirationTime
+			token.ExpirationTTL = 0
+		}
+
+		if token.HasExpirationTime() {
+			if token.ExpirationTime.Sub(token.CreateTime) < aclTokenMinExpirationTTL {
+				return fmt.Errorf("Token Expiration Time must be at least %s after the Create Time", aclTokenMinExpirationTTL)
+			}
+		} else {
+			token.ExpirationTime = nil
+		}
 	} else {
 		// Token Update
 		if _, err := uuid.ParseUUID(token.AccessorID); err != nil {
@@ -365,7 +461,7 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 		if err != nil {
 			return fmt.Errorf("Failed to lookup the acl token %q: %v", token.AccessorID, err)
 		}
-		if existing == nil {
+		if existing == nil || existing.IsExpired(time.Now()) {
 			return fmt.Errorf("Cannot find token %q", token.AccessorID)
 		}
 		if token.SecretID == "" {
@@ -379,6 +475,17 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 			return fmt.Errorf("cannot toggle local mode of %s", token.AccessorID)
 		}
 
+		// The expiration time is fixed when the token is created and may
+		// only be echoed back unchanged.
+		if token.ExpirationTTL != 0 {
+			return fmt.Errorf("Cannot change expiration time of %s", token.AccessorID)
+		}
+		if !token.HasExpirationTime() {
+			token.ExpirationTime = existing.ExpirationTime
+		} else if !existing.HasExpirationTime() || !token.ExpirationTime.Equal(*existing.ExpirationTime) {
+			return fmt.Errorf("Cannot change expiration time of %s", token.AccessorID)
+		}
+
 		if upgrade {
 			token.CreateTime = time.Now()
 		} else {
@@ -413,6 +520,39 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 	}
 	token.Policies = policies
 
+	roleIDs := make(map[string]struct{})
+	var roles []structs.ACLTokenRoleLink
+
+	// Validate all the role names and convert them to role IDs
+	for _, link := range token.Roles {
+		if link.ID == "" {
+			_, role, err := state.ACLRoleGetByName(nil, link.Name)
+			if err != nil {
+				return fmt.Errorf("Error looking up role for name %q: %v", link.Name, err)
+			}
+			if role == nil {
+				return fmt.Errorf("No such ACL role with name %q", link.Name)
+			}
+			link.ID = role.ID
+		}
+
+		// Do not store the role name within raft/memdb as the role could be renamed in the future.
+		link.Name = ""
+
+		// dedup role links by id
+		if _, ok := roleIDs[link.ID]; !ok {
+			roles = append(roles, link)
+			roleIDs[link.ID] = struct{}{}
+		}
+	}
+	token.Roles = roles
+
+	serviceIdentities, err := validateServiceIdentities(token.ServiceIdentities, token.Local)
+	if err != nil {
+		return err
+	}
+	token.ServiceIdentities = serviceIdentities
+
 	if token.Rules != "" {
 		return fmt.Errorf("Rules cannot be specified for this token")
 	}
@@ -421,6 +561,10 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 		return fmt.Errorf("Type cannot be specified for this token")
 	}
 
+	if err := structs.ValidateACLLabels(token.Labels); err != nil {
+		return fmt.Errorf("Invalid Token: %v", err)
+	}
+
 	token.SetHash(true)
 
 	req := &structs.ACLTokenBatchSetRequest{
@@ -548,13 +692,20 @@ func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTok
 
 	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
-			index, tokens, err := state.ACLTokenList(ws, args.IncludeLocal, args.IncludeGlobal, args.Policy)
+			index, tokens, err := state.ACLTokenList(ws, args.IncludeLocal, args.IncludeGlobal, args.Policy, args.Role)
 			if err != nil {
 				return err
 			}
 
+			now := time.Now()
 			stubs := make([]*structs.ACLTokenListStub, 0, len(tokens))
 			for _, token := range tokens {
+				if token.IsExpired(now) {
+					continue
+				}
+				if !structs.SatisfiesMetaFilters(token.Labels, args.Labels) {
+					continue
+				}
 				stubs = append(stubs, token.Stub())
 			}
 			reply.Index, reply.Tokens = index, stubs
@@ -589,10 +740,8 @@ func (a *ACL) TokenBatchRead(args *structs.ACLTokenBatchGetRequest, reply *struc
 				return err
 			}
 
-			a.srv.filterACLWithAuthorizer(rule, &tokens)
-
 			reply.Index, reply.Tokens = index, tokens
-			reply.Redacted = !rule.ACLWrite()
+			a.srv.filterACLWithAuthorizer(rule, reply)
 			return nil
 		})
 }
@@ -652,6 +801,75 @@ func (a *ACL) PolicyBatchRead(args *structs.ACLPolicyBatchGetRequest, reply *str
 		})
 }
 
+// PolicyUsage returns the tokens and roles that link to a policy. Local tokens
+// are only visible in their own datacenter, so the tokens of other datacenters
+// are not included.
+func (a *ACL) PolicyUsage(args *structs.ACLPolicyGetRequest, reply *structs.ACLPolicyUsageResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.ACLDatacenter
+	}
+
+	if done, err := a.srv.forward("ACL.PolicyUsage", args, args, reply); done {
+		return err
+	}
+
+	if rule, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if rule == nil || !rule.ACLRead() {
+		return acl.ErrPermissionDenied
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, policy, err := state.ACLPolicyGetByID(ws, args.PolicyID)
+			if err != nil {
+				return err
+			}
+			if policy == nil {
+				reply.Index, reply.Usage = index, nil
+				return nil
+			}
+
+			tokenIndex, tokens, err := state.ACLTokenList(ws, true, true, args.PolicyID, "")
+			if err != nil {
+				return err
+			}
+			if tokenIndex > index {
+				index = tokenIndex
+			}
+
+			roleIndex, roles, err := state.ACLRoleList(ws, args.PolicyID)
+			if err != nil {
+				return err
+			}
+			if roleIndex > index {
+				index = roleIndex
+			}
+
+			now := time.Now()
+			usage := &structs.ACLPolicyUsage{
+				Tokens: make(structs.ACLTokenListStubs, 0, len(tokens)),
+				Roles:  roles,
+			}
+			for _, token := range tokens {
+				if token.IsExpired(now) {
+					continue
+				}
+				usage.Tokens = append(usage.Tokens, token.Stub())
+			}
+			if usage.Roles == nil {
+				usage.Roles = make(structs.ACLRoles, 0)
+			}
+
+			reply.Index, reply.Usage = index, usage
+			return nil
+		})
+}
+
 func (a *ACL) PolicySet(args *structs.ACLPolicySetRequest, reply *structs.ACLPolicy) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -690,6 +908,13 @@ func (a *ACL) PolicySet(args *structs.ACLPolicySetRequest, reply *structs.ACLPol
 		return fmt.Errorf("Invalid Policy: invalid Name. Only alphanumeric characters, '-' and '_' are allowed")
 	}
 
+	// enforce the cluster's naming convention, if one is configured
+	if re := a.srv.config.ACLPolicyNamePattern; re != nil && policy.ID != structs.ACLPolicyGlobalManagementID {
+		if !re.MatchString(policy.Name) {
+			return fmt.Errorf("Invalid Policy: Name %q does not match the configured naming pattern %q", policy.Name, re.String())
+		}
+	}
+
 	if policy.ID == "" {
 		// with no policy ID one will be generated
 		var err error
@@ -737,6 +962,10 @@ func (a *ACL) PolicySet(args *structs.ACLPolicySetRequest, reply *structs.ACLPol
 		}
 	}
 
+	if err := structs.ValidateACLLabels(policy.Labels); err != nil {
+		return fmt.Errorf("Invalid Policy: %v", err)
+	}
+
 	// validate the rules
 	_, err := acl.NewPolicyFromSource("", 0, policy.Rules, policy.Syntax, a.srv.sentinel)
 	if err != nil {
@@ -850,6 +1079,9 @@ func (a *ACL) PolicyList(args *structs.ACLPolicyListRequest, reply *structs.ACLP
 
 			var stubs structs.ACLPolicyListStubs
 			for _, policy := range policies {
+				if !structs.SatisfiesMetaFilters(policy.Labels, args.Labels) {
+					continue
+				}
 				stubs = append(stubs, policy.Stub())
 			}
 
@@ -875,10 +1107,21 @@ func (a *ACL) PolicyResolve(args *structs.ACLPolicyBatchGetRequest, reply *struc
 		return err
 	}
 
+	// policies linked through the token's roles may be resolved too
+	roles, err := a.srv.acls.resolveRolesForIdentity(identity)
+	if err != nil {
+		return err
+	}
+
 	idMap := make(map[string]*structs.ACLPolicy)
 	for _, policyID := range identity.PolicyIDs() {
 		idMap[policyID] = nil
 	}
+	for _, role := range roles {
+		for _, policyID := range role.PolicyIDs() {
+			idMap[policyID] = nil
+		}
+	}
 	for _, policy := range policies {
 		idMap[policy.ID] = policy
 	}
@@ -901,6 +1144,414 @@ func (a *ACL) PolicyResolve(args *structs.ACLPolicyBatchGetRequest, reply *struc
 	return nil
 }
 
+func (a *ACL) RoleRead(args *structs.ACLRoleGetRequest, reply *structs.ACLRoleResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.forward("ACL.RoleRead", args, args, reply); done {
+		return err
+	}
+
+	if rule, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if rule == nil || !rule.ACLRead() {
+		return acl.ErrPermissionDenied
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			var (
+				index uint64
+				role  *structs.ACLRole
+				err   error
+			)
+			if args.RoleID != "" {
+				index, role, err = state.ACLRoleGetByID(ws, args.RoleID)
+			} else {
+				index, role, err = state.ACLRoleGetByName(ws, args.RoleName)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			reply.Index, reply.Role = index, role
+			return nil
+		})
+}
+
+func (a *ACL) RoleBatchRead(args *structs.ACLRoleBatchGetRequest, reply *structs.ACLRoleBatchResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.forward("ACL.RoleBatchRead", args, args, reply); done {
+		return err
+	}
+
+	if rule, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if rule == nil || !rule.ACLRead() {
+		return acl.ErrPermissionDenied
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, roles, err := state.ACLRoleBatchGet(ws, args.RoleIDs)
+			if err != nil {
+				return err
+			}
+
+			reply.Index, reply.Roles = index, roles
+			return nil
+		})
+}
+
+func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.InACLDatacenter() {
+		args.Datacenter = a.srv.config.ACLDatacenter
+	}
+
+	if done, err := a.srv.forward("ACL.RoleSet", args, args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "role", "upsert"}, time.Now())
+
+	// Verify token is permitted to modify ACLs
+	if rule, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if rule == nil || !rule.ACLWrite() {
+		return acl.ErrPermissionDenied
+	}
+
+	role := &args.Role
+	state := a.srv.fsm.State()
+
+	// Almost all of the checks here are also done in the state store. However,
+	// we want to prevent the raft operations when we know they are going to fail
+	// so we still do them here.
+
+	// ensure a name is set
+	if role.Name == "" {
+		return fmt.Errorf("Invalid Role: no Name is set")
+	}
+
+	if !validRoleName.MatchString(role.Name) {
+		return fmt.Errorf("Invalid Role: invalid Name. Only alphanumeric characters, '-' and '_' are allowed")
+	}
+
+	// enforce the cluster's naming convention, if one is configured
+	if re := a.srv.config.ACLRoleNamePattern; re != nil && !re.MatchString(role.Name) {
+		return fmt.Errorf("Invalid Role: Name %q does not match the configured naming pattern %q", role.Name, re.String())
+	}
+
+	if role.ID == "" {
+		// with no role ID one will be generated
+		var err error
+
+		role.ID, err = lib.GenerateUUID(a.srv.checkRoleUUID)
+		if err != nil {
+			return err
+		}
+
+		// validate the name is unique
+		if _, existing, err := state.ACLRoleGetByName(nil, role.Name); err != nil {
+			return fmt.Errorf("acl role lookup by name failed: %v", err)
+		} else if existing != nil {
+			return fmt.Errorf("Invalid Role: A Role with Name %q already exists", role.Name)
+		}
+	} else {
+		if _, err := uuid.ParseUUID(role.ID); err != nil {
+			return fmt.Errorf("Role ID invalid UUID")
+		}
+
+		// Verify the role exists
+		_, existing, err := state.ACLRoleGetByID(nil, role.ID)
+		if err != nil {
+			return fmt.Errorf("acl role lookup failed: %v", err)
+		} else if existing == nil {
+			return fmt.Errorf("cannot find role %s", role.ID)
+		}
+
+		if existing.Name != role.Name {
+			if _, nameMatch, err := state.ACLRoleGetByName(nil, role.Name); err != nil {
+				return fmt.Errorf("acl role lookup by name failed: %v", err)
+			} else if nameMatch != nil {
+				return fmt.Errorf("Invalid Role: A role with name %q already exists", role.Name)
+			}
+		}
+	}
+
+	policyIDs := make(map[string]struct{})
+	var policies []structs.ACLRolePolicyLink
+
+	// Validate all the policy names and convert them to policy IDs
+	for _, link := range role.Policies {
+		if link.ID == "" {
+			_, policy, err := state.ACLPolicyGetByName(nil, link.Name)
+			if err != nil {
+				return fmt.Errorf("Error looking up policy for name %q: %v", link.Name, err)
+			}
+			if policy == nil {
+				return fmt.Errorf("No such ACL policy with name %q", link.Name)
+			}
+			link.ID = policy.ID
+		}
+
+		// Do not store the policy name within raft/memdb as the policy could be renamed in the future.
+		link.Name = ""
+
+		// dedup policy links by id
+		if _, ok := policyIDs[link.ID]; !ok {
+			policies = append(policies, link)
+			policyIDs[link.ID] = struct{}{}
+		}
+	}
+	role.Policies = policies
+
+	serviceIdentities, err := validateServiceIdentities(role.ServiceIdentities, false)
+	if err != nil {
+		return fmt.Errorf("Invalid Role: %v", err)
+	}
+	role.ServiceIdentities = serviceIdentities
+
+	if err := structs.ValidateACLLabels(role.Labels); err != nil {
+		return fmt.Errorf("Invalid Role: %v", err)
+	}
+
+	// calculate the hash for this role
+	role.SetHash(true)
+
+	req := &structs.ACLRoleBatchSetRequest{
+		Roles: structs.ACLRoles{role},
+	}
+
+	resp, err := a.srv.raftApply(structs.ACLRoleSetRequestType, req)
+	if err != nil {
+		return fmt.Errorf("Failed to apply role upsert request: %v", err)
+	}
+
+	// Remove from the cache to prevent stale cache usage
+	a.srv.acls.cache.RemoveRole(role.ID)
+
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	if _, role, err := a.srv.fsm.State().ACLRoleGetByID(nil, role.ID); err == nil && role != nil {
+		*reply = *role
+	}
+
+	return nil
+}
+
+func (a *ACL) RoleDelete(args *structs.ACLRoleDeleteRequest, reply *string) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.InACLDatacenter() {
+		args.Datacenter = a.srv.config.ACLDatacenter
+	}
+
+	if done, err := a.srv.forward("ACL.RoleDelete", args, args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "role", "delete"}, time.Now())
+
+	// Verify token is permitted to modify ACLs
+	if rule, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if rule == nil || !rule.ACLWrite() {
+		return acl.ErrPermissionDenied
+	}
+
+	_, role, err := a.srv.fsm.State().ACLRoleGetByID(nil, args.RoleID)
+	if err != nil {
+		return err
+	}
+
+	if role == nil {
+		return nil
+	}
+
+	req := structs.ACLRoleBatchDeleteRequest{
+		RoleIDs: []string{args.RoleID},
+	}
+
+	resp, err := a.srv.raftApply(structs.ACLRoleDeleteRequestType, &req)
+	if err != nil {
+		return fmt.Errorf("Failed to apply role delete request: %v", err)
+	}
+
+	a.srv.acls.cache.RemoveRole(role.ID)
+
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	*reply = role.Name
+
+	return nil
+}
+
+func (a *ACL) RoleList(args *structs.ACLRoleListRequest, reply *structs.ACLRoleListResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.forward("ACL.RoleList", args, args, reply); done {
+		return err
+	}
+
+	if rule, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if rule == nil || !rule.ACLRead() {
+		return acl.ErrPermissionDenied
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, roles, err := state.ACLRoleList(ws, args.Policy)
+			if err != nil {
+				return err
+			}
+
+			var filtered structs.ACLRoles
+			for _, role := range roles {
+				if !structs.SatisfiesMetaFilters(role.Labels, args.Labels) {
+					continue
+				}
+				filtered = append(filtered, role)
+			}
+
+			reply.Index, reply.Roles = index, filtered
+			return nil
+		})
+}
+
+// RoleResolve is used to retrieve a subset of the roles associated with a given token
+// The role ids in the args simply act as a filter on the role set assigned to the token
+func (a *ACL) RoleResolve(args *structs.ACLRoleBatchGetRequest, reply *structs.ACLRoleBatchResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.forward("ACL.RoleResolve", args, args, reply); done {
+		return err
+	}
+
+	// get full list of roles for this token
+	identity, err := a.srv.acls.resolveIdentityFromToken(args.Token)
+	if err != nil {
+		return err
+	} else if identity == nil {
+		return acl.ErrNotFound
+	}
+
+	roles, err := a.srv.acls.resolveRolesForIdentity(identity)
+	if err != nil {
+		return err
+	}
+
+	idMap := make(map[string]*structs.ACLRole)
+	for _, roleID := range identity.RoleIDs() {
+		idMap[roleID] = nil
+	}
+	for _, role := range roles {
+		idMap[role.ID] = role
+	}
+
+	for _, roleID := range args.RoleIDs {
+		if role, ok := idMap[roleID]; ok {
+			// only add non-deleted roles
+			if role != nil {
+				reply.Roles = append(reply.Roles, role)
+			}
+		} else {
+			// send a permission denied to indicate that the request included
+			// role ids not associated with this token
+			return acl.ErrPermissionDenied
+		}
+	}
+
+	a.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// Authorize checks whether a token is granted some access to a resource. The
+// token is resolved and its policies are enforced exactly as they are for any
+// other request, and the rule that made the decision is returned with the
+// result to help with debugging denied requests.
+func (a *ACL) Authorize(args *structs.ACLAuthorizeRequest, reply *structs.ACLAuthorizeResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	// Without token replication other tokens can only be looked up in the
+	// ACL datacenter.
+	if args.AccessorID != "" && !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.ACLDatacenter
+	}
+
+	if done, err := a.srv.forward("ACL.Authorize", args, args, reply); done {
+		return err
+	}
+
+	secretID := args.Token
+	if args.AccessorID != "" {
+		// Checking another token requires the same privileges as reading it
+		rule, err := a.srv.ResolveToken(args.Token)
+		if err != nil {
+			return err
+		} else if rule == nil || !rule.ACLRead() {
+			return acl.ErrPermissionDenied
+		}
+
+		_, token, err := a.srv.fsm.State().ACLTokenGetByAccessor(nil, args.AccessorID)
+		if err != nil {
+			return err
+		} else if token == nil || token.IsExpired(time.Now()) {
+			return acl.ErrNotFound
+		}
+		secretID = token.SecretID
+	}
+
+	authz, err := a.srv.ResolveToken(secretID)
+	if err != nil {
+		return err
+	} else if authz == nil {
+		return acl.ErrDisabled
+	}
+
+	allow, err := acl.Check(authz, args.Resource, args.Segment, args.Access)
+	if err != nil {
+		return err
+	}
+
+	reply.Authorization = structs.ACLAuthorization{
+		Resource:      args.Resource,
+		Segment:       args.Segment,
+		Access:        args.Access,
+		Allow:         allow,
+		DefaultPolicy: a.srv.config.ACLDefaultPolicy,
+	}
+	if policyAuthz, ok := authz.(*acl.PolicyAuthorizer); ok {
+		reply.Authorization.Rule = policyAuthz.MatchingRule(args.Resource, args.Segment)
+	}
+	a.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
 // makeACLETag returns an ETag for the given parent and policy.
 func makeACLETag(parent string, policy *acl.Policy) string {
 	return fmt.Sprintf("%s:%s", parent, policy.ID)
@@ -934,7 +1585,7 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicyResolveLegacyRequest, reply *stru
 
 	// Setup the response
 	reply.ETag = etag
-	reply.TTL = a.srv.config.ACLTokenTTL
+	reply.TTL, _ = a.srv.acls.cacheTTLs()
 	a.srv.setQueryMeta(&reply.QueryMeta)
 
 	// Only send the policy on an Etag mis-match
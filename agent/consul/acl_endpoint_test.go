@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -695,6 +696,40 @@ func TestACLEndpoint_TokenRead(t *testing.T) {
 		require.Nil(t, resp.Token)
 		require.EqualError(t, err, "failed acl token lookup: failed acl token lookup: index error: UUID must be 36 characters")
 	})
+
+	t.Run("redacts the secret without acl:write", func(t *testing.T) {
+		reader := upsertTestACLReadToken(t, codec, "root", "dc1")
+
+		req := structs.ACLTokenGetRequest{
+			Datacenter:   "dc1",
+			TokenID:      token.AccessorID,
+			TokenIDType:  structs.ACLTokenAccessor,
+			QueryOptions: structs.QueryOptions{Token: reader.SecretID},
+		}
+
+		resp := structs.ACLTokenResponse{}
+
+		err := acl.TokenRead(&req, &resp)
+		require.NoError(t, err)
+		require.True(t, resp.Redacted)
+		require.Equal(t, redactedToken, resp.Token.SecretID)
+		require.Equal(t, token.AccessorID, resp.Token.AccessorID)
+
+		// Lookups by secret are made by callers already holding it.
+		req.TokenID = token.SecretID
+		req.TokenIDType = structs.ACLTokenSecret
+		resp = structs.ACLTokenResponse{}
+
+		err = acl.TokenRead(&req, &resp)
+		require.NoError(t, err)
+		require.False(t, resp.Redacted)
+		require.Equal(t, token.SecretID, resp.Token.SecretID)
+
+		// The token in the state store must be left alone.
+		_, stored, err := s1.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID)
+		require.NoError(t, err)
+		require.Equal(t, token.SecretID, stored.SecretID)
+	})
 }
 
 func TestACLEndpoint_TokenClone(t *testing.T) {
@@ -712,11 +747,25 @@ func TestACLEndpoint_TokenClone(t *testing.T) {
 
 	testrpc.WaitForLeader(t, s1.RPC, "dc1")
 
-	t1, err := upsertTestToken(codec, "root", "dc1")
+	role, err := upsertTestRole(codec, "root", "dc1")
 	require.NoError(t, err)
 
 	acl := ACL{srv: s1}
 
+	t1 := structs.ACLToken{}
+	err = acl.TokenSet(&structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			Description: "User token",
+			Roles:       []structs.ACLTokenRoleLink{structs.ACLTokenRoleLink{ID: role.ID}},
+			ServiceIdentities: []*structs.ACLServiceIdentity{
+				&structs.ACLServiceIdentity{ServiceName: "web"},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}, &t1)
+	require.NoError(t, err)
+
 	req := structs.ACLTokenSetRequest{
 		Datacenter:   "dc1",
 		ACLToken:     structs.ACLToken{AccessorID: t1.AccessorID},
@@ -730,6 +779,8 @@ func TestACLEndpoint_TokenClone(t *testing.T) {
 
 	require.Equal(t, t1.Description, t2.Description)
 	require.Equal(t, t1.Policies, t2.Policies)
+	require.Equal(t, t1.Roles, t2.Roles)
+	require.Equal(t, t1.ServiceIdentities, t2.ServiceIdentities)
 	require.Equal(t, t1.Rules, t2.Rules)
 	require.Equal(t, t1.Local, t2.Local)
 	require.NotEqual(t, t1.AccessorID, t2.AccessorID)
@@ -806,6 +857,182 @@ func TestACLEndpoint_TokenSet(t *testing.T) {
 		require.Equal(t, token.Description, "new-description")
 		require.Equal(t, token.AccessorID, resp.AccessorID)
 	})
+
+	t.Run("Create it with chosen IDs", func(t *testing.T) {
+		accessor, err := uuid.GenerateUUID()
+		require.NoError(t, err)
+		secret, err := uuid.GenerateUUID()
+		require.NoError(t, err)
+
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				AccessorID:  accessor,
+				SecretID:    secret,
+				Description: "migrated",
+			},
+			Create:       true,
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+
+		resp := structs.ACLToken{}
+		require.NoError(t, acl.TokenSet(&req, &resp))
+		require.Equal(t, accessor, resp.AccessorID)
+		require.Equal(t, secret, resp.SecretID)
+
+		// Creating it again collides with the existing token
+		resp = structs.ACLToken{}
+		req.ACLToken.SecretID = ""
+		err = acl.TokenSet(&req, &resp)
+		require.EqualError(t, err, "Invalid Token: AccessorID is already in use")
+
+		req.ACLToken.AccessorID = ""
+		req.ACLToken.SecretID = secret
+		err = acl.TokenSet(&req, &resp)
+		require.EqualError(t, err, "Invalid Token: SecretID is already in use")
+
+		req.ACLToken.SecretID = structs.ACLTokenAnonymousID
+		err = acl.TokenSet(&req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "reserved")
+	})
+
+	t.Run("Create it with service identities", func(t *testing.T) {
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				Description: "foobar",
+				ServiceIdentities: []*structs.ACLServiceIdentity{
+					&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+					&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc2"}},
+					&structs.ACLServiceIdentity{ServiceName: "db"},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+
+		resp := structs.ACLToken{}
+
+		err := acl.TokenSet(&req, &resp)
+		require.NoError(t, err)
+
+		tokenResp, err := retrieveTestToken(codec, "root", "dc1", resp.AccessorID)
+		require.NoError(t, err)
+		require.Equal(t, []*structs.ACLServiceIdentity{
+			&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1", "dc2"}},
+			&structs.ACLServiceIdentity{ServiceName: "db"},
+		}, tokenResp.Token.ServiceIdentities)
+	})
+
+	t.Run("Create it with an expiration TTL", func(t *testing.T) {
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				Description:   "expiring",
+				ExpirationTTL: time.Hour,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+
+		resp := structs.ACLToken{}
+		require.NoError(t, acl.TokenSet(&req, &resp))
+
+		tokenResp, err := retrieveTestToken(codec, "root", "dc1", resp.AccessorID)
+		require.NoError(t, err)
+		token := tokenResp.Token
+		require.NotNil(t, token.ExpirationTime)
+		require.Zero(t, token.ExpirationTTL)
+		require.True(t, token.ExpirationTime.Equal(token.CreateTime.Add(time.Hour)))
+
+		// Updates keep the expiration time when it is left out or echoed back.
+		for _, expirationTime := range []*time.Time{nil, token.ExpirationTime} {
+			req = structs.ACLTokenSetRequest{
+				Datacenter: "dc1",
+				ACLToken: structs.ACLToken{
+					AccessorID:     token.AccessorID,
+					Description:    "still expiring",
+					ExpirationTime: expirationTime,
+				},
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+			require.NoError(t, acl.TokenSet(&req, &resp))
+			require.NotNil(t, resp.ExpirationTime)
+			require.True(t, resp.ExpirationTime.Equal(*token.ExpirationTime))
+		}
+
+		// But they cannot change it.
+		later := token.ExpirationTime.Add(time.Hour)
+		for _, update := range []structs.ACLToken{
+			{AccessorID: token.AccessorID, ExpirationTTL: time.Hour},
+			{AccessorID: token.AccessorID, ExpirationTime: &later},
+		} {
+			req = structs.ACLTokenSetRequest{
+				Datacenter:   "dc1",
+				ACLToken:     update,
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+			err := acl.TokenSet(&req, &resp)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "Cannot change expiration time")
+		}
+	})
+
+	soon := time.Now().Add(time.Second)
+	invalidExpiration := map[string]structs.ACLToken{
+		"negative TTL":            structs.ACLToken{ExpirationTTL: -time.Hour},
+		"too short TTL":           structs.ACLToken{ExpirationTTL: time.Second},
+		"too early time":          structs.ACLToken{ExpirationTime: &soon},
+		"both TTL and time given": structs.ACLToken{ExpirationTTL: time.Hour, ExpirationTime: &soon},
+	}
+	for name, token := range invalidExpiration {
+		token := token
+		t.Run("Create it with "+name, func(t *testing.T) {
+			req := structs.ACLTokenSetRequest{
+				Datacenter:   "dc1",
+				ACLToken:     token,
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+
+			resp := structs.ACLToken{}
+			err := acl.TokenSet(&req, &resp)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "Expiration")
+		})
+	}
+
+	invalid := map[string]structs.ACLToken{
+		"missing service name": structs.ACLToken{
+			ServiceIdentities: []*structs.ACLServiceIdentity{
+				&structs.ACLServiceIdentity{},
+			},
+		},
+		"invalid service name": structs.ACLToken{
+			ServiceIdentities: []*structs.ACLServiceIdentity{
+				&structs.ACLServiceIdentity{ServiceName: "Web*"},
+			},
+		},
+		"datacenters on local token": structs.ACLToken{
+			Local: true,
+			ServiceIdentities: []*structs.ACLServiceIdentity{
+				&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+			},
+		},
+	}
+	for name, token := range invalid {
+		token := token
+		t.Run("Create it with "+name, func(t *testing.T) {
+			req := structs.ACLTokenSetRequest{
+				Datacenter:   "dc1",
+				ACLToken:     token,
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+
+			resp := structs.ACLToken{}
+			err := acl.TokenSet(&req, &resp)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "Service identity")
+		})
+	}
 }
 
 func TestACLEndpoint_TokenSet_anon(t *testing.T) {
@@ -1054,6 +1281,72 @@ func TestACLEndpoint_TokenList(t *testing.T) {
 	require.Subset(t, retrievedTokens, tokens)
 }
 
+func TestACLEndpoint_TokenList_labels(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	upsert := func(labels map[string]string) (*structs.ACLToken, error) {
+		arg := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				Description: "labeled token",
+				Labels:      labels,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		var out structs.ACLToken
+		err := msgpackrpc.CallWithCodec(codec, "ACL.TokenSet", &arg, &out)
+		return &out, err
+	}
+
+	t1, err := upsert(map[string]string{"team": "payments", "env": "prod"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "payments", "env": "prod"}, t1.Labels)
+
+	t2, err := upsert(map[string]string{"team": "payments", "env": "staging"})
+	require.NoError(t, err)
+
+	_, err = upsert(map[string]string{"team": "search"})
+	require.NoError(t, err)
+
+	_, err = upsert(map[string]string{"consul-team": "payments"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid label")
+
+	acl := ACL{srv: s1}
+
+	list := func(labels map[string]string) []string {
+		req := structs.ACLTokenListRequest{
+			Datacenter:   "dc1",
+			Labels:       labels,
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLTokenListResponse{}
+		require.NoError(t, acl.TokenList(&req, &resp))
+
+		var ids []string
+		for _, v := range resp.Tokens {
+			ids = append(ids, v.AccessorID)
+		}
+		return ids
+	}
+
+	require.ElementsMatch(t, []string{t1.AccessorID, t2.AccessorID}, list(map[string]string{"team": "payments"}))
+	require.ElementsMatch(t, []string{t2.AccessorID}, list(map[string]string{"team": "payments", "env": "staging"}))
+	require.Empty(t, list(map[string]string{"team": "missing"}))
+}
+
 func TestACLEndpoint_TokenBatchRead(t *testing.T) {
 	t.Parallel()
 
@@ -1095,6 +1388,19 @@ func TestACLEndpoint_TokenBatchRead(t *testing.T) {
 		retrievedTokens = append(retrievedTokens, v.AccessorID)
 	}
 	require.EqualValues(t, retrievedTokens, tokens)
+	require.False(t, resp.Redacted)
+
+	reader := upsertTestACLReadToken(t, codec, "root", "dc1")
+	req.Token = reader.SecretID
+	resp = structs.ACLTokenBatchResponse{}
+
+	err = acl.TokenBatchRead(&req, &resp)
+	require.NoError(t, err)
+	require.True(t, resp.Redacted)
+	require.Len(t, resp.Tokens, 2)
+	for _, v := range resp.Tokens {
+		require.Equal(t, redactedToken, v.SecretID)
+	}
 }
 
 func TestACLEndpoint_PolicyRead(t *testing.T) {
@@ -1179,6 +1485,95 @@ func TestACLEndpoint_PolicyBatchRead(t *testing.T) {
 	require.EqualValues(t, retrievedPolicies, policies)
 }
 
+func TestACLEndpoint_PolicyUsage(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	policy, err := upsertTestPolicy(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	// Neither the unlinked token nor the one linking the policy through the
+	// role should show up in the usage.
+	_, err = upsertTestToken(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	roleReq := structs.ACLRoleSetRequest{
+		Datacenter: "dc1",
+		Role: structs.ACLRole{
+			Name:     "usage",
+			Policies: []structs.ACLRolePolicyLink{{ID: policy.ID}},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var role structs.ACLRole
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.RoleSet", &roleReq, &role))
+
+	tokenReq := structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			Description: "with policy",
+			Policies:    []structs.ACLTokenPolicyLink{{ID: policy.ID}},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var token structs.ACLToken
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TokenSet", &tokenReq, &token))
+
+	tokenReq.ACLToken = structs.ACLToken{
+		Description: "with role",
+		Roles:       []structs.ACLTokenRoleLink{{ID: role.ID}},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TokenSet", &tokenReq, &structs.ACLToken{}))
+
+	endpoint := ACL{srv: s1}
+
+	t.Run("used", func(t *testing.T) {
+		req := structs.ACLPolicyGetRequest{
+			Datacenter:   "dc1",
+			PolicyID:     policy.ID,
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLPolicyUsageResponse{}
+		require.NoError(t, endpoint.PolicyUsage(&req, &resp))
+		require.NotNil(t, resp.Usage)
+		require.Len(t, resp.Usage.Tokens, 1)
+		require.Equal(t, token.AccessorID, resp.Usage.Tokens[0].AccessorID)
+		require.Len(t, resp.Usage.Roles, 1)
+		require.Equal(t, role.ID, resp.Usage.Roles[0].ID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := structs.ACLPolicyGetRequest{
+			Datacenter:   "dc1",
+			PolicyID:     "c9e6a4c4-8e1b-4a3d-b5b8-0e2c4f5d6a11",
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLPolicyUsageResponse{}
+		require.NoError(t, endpoint.PolicyUsage(&req, &resp))
+		require.Nil(t, resp.Usage)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		req := structs.ACLPolicyGetRequest{
+			Datacenter: "dc1",
+			PolicyID:   policy.ID,
+		}
+		resp := structs.ACLPolicyUsageResponse{}
+		err := endpoint.PolicyUsage(&req, &resp)
+		require.True(t, acl.IsErrPermissionDenied(err))
+	})
+}
+
 func TestACLEndpoint_PolicySet(t *testing.T) {
 	t.Parallel()
 
@@ -1257,6 +1652,74 @@ func TestACLEndpoint_PolicySet(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_PolicySet_namePattern(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+		c.ACLPolicyNamePattern = regexp.MustCompile(`^team-[a-z]+-`)
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	acl := ACL{srv: s1}
+
+	// A non-matching name is rejected
+	{
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				Name:  "payments-read",
+				Rules: "service \"\" { policy = \"read\" }",
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		resp := structs.ACLPolicy{}
+
+		err := acl.PolicySet(&req, &resp)
+		require.EqualError(t, err, `Invalid Policy: Name "payments-read" does not match the configured naming pattern "^team-[a-z]+-"`)
+	}
+
+	// A matching name is accepted
+	{
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				Name:  "team-payments-read",
+				Rules: "service \"\" { policy = \"read\" }",
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		resp := structs.ACLPolicy{}
+
+		require.NoError(t, acl.PolicySet(&req, &resp))
+		require.NotEmpty(t, resp.ID)
+	}
+
+	// The builtin global-management policy is exempt
+	{
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				ID:          structs.ACLPolicyGlobalManagementID,
+				Name:        "global-management",
+				Description: "updated",
+				Rules:       structs.ACLPolicyGlobalManagement,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		resp := structs.ACLPolicy{}
+
+		require.NoError(t, acl.PolicySet(&req, &resp))
+	}
+}
+
 func TestACLEndpoint_PolicySet_globalManagement(t *testing.T) {
 	t.Parallel()
 
@@ -1428,7 +1891,7 @@ func TestACLEndpoint_PolicyList(t *testing.T) {
 	require.Subset(t, retrievedPolicies, policies)
 }
 
-func TestACLEndpoint_PolicyResolve(t *testing.T) {
+func TestACLEndpoint_PolicyList_labels(t *testing.T) {
 	t.Parallel()
 
 	dir1, s1 := testServerWithConfig(t, func(c *Config) {
@@ -1443,23 +1906,76 @@ func TestACLEndpoint_PolicyResolve(t *testing.T) {
 
 	testrpc.WaitForLeader(t, s1.RPC, "dc1")
 
-	p1, err := upsertTestPolicy(codec, "root", "dc1")
+	upsert := func(name string, labels map[string]string) (*structs.ACLPolicy, error) {
+		arg := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				Name:   name,
+				Labels: labels,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		var out structs.ACLPolicy
+		err := msgpackrpc.CallWithCodec(codec, "ACL.PolicySet", &arg, &out)
+		return &out, err
+	}
+
+	p1, err := upsert("payments-prod", map[string]string{"team": "payments", "env": "prod"})
 	require.NoError(t, err)
 
-	p2, err := upsertTestPolicy(codec, "root", "dc1")
+	_, err = upsert("search", map[string]string{"team": "search"})
 	require.NoError(t, err)
 
+	_, err = upsert("invalid", map[string]string{"": "payments"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid label")
+
 	acl := ACL{srv: s1}
 
-	policies := []string{p1.ID, p2.ID}
+	req := structs.ACLPolicyListRequest{
+		Datacenter:   "dc1",
+		Labels:       map[string]string{"team": "payments"},
+		QueryOptions: structs.QueryOptions{Token: "root"},
+	}
+	resp := structs.ACLPolicyListResponse{}
+	require.NoError(t, acl.PolicyList(&req, &resp))
+	require.Len(t, resp.Policies, 1)
+	require.Equal(t, p1.ID, resp.Policies[0].ID)
+	require.Equal(t, p1.Labels, resp.Policies[0].Labels)
+}
 
-	// Assign the policies to a token
-	tokenUpsertReq := structs.ACLTokenSetRequest{
-		Datacenter: "dc1",
-		ACLToken: structs.ACLToken{
-			Policies: []structs.ACLTokenPolicyLink{
-				structs.ACLTokenPolicyLink{
-					ID: p1.ID,
+func TestACLEndpoint_PolicyResolve(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	p1, err := upsertTestPolicy(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	p2, err := upsertTestPolicy(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	acl := ACL{srv: s1}
+
+	policies := []string{p1.ID, p2.ID}
+
+	// Assign the policies to a token
+	tokenUpsertReq := structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			Policies: []structs.ACLTokenPolicyLink{
+				structs.ACLTokenPolicyLink{
+					ID: p1.ID,
 				},
 				structs.ACLTokenPolicyLink{
 					ID: p2.ID,
@@ -1490,6 +2006,497 @@ func TestACLEndpoint_PolicyResolve(t *testing.T) {
 	require.EqualValues(t, retrievedPolicies, policies)
 }
 
+func TestACLEndpoint_RoleRead(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	role, err := upsertTestRole(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	acl := ACL{srv: s1}
+
+	t.Run("by id", func(t *testing.T) {
+		req := structs.ACLRoleGetRequest{
+			Datacenter:   "dc1",
+			RoleID:       role.ID,
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLRoleResponse{}
+		require.NoError(t, acl.RoleRead(&req, &resp))
+		require.Equal(t, role, resp.Role)
+	})
+
+	t.Run("by name", func(t *testing.T) {
+		req := structs.ACLRoleGetRequest{
+			Datacenter:   "dc1",
+			RoleName:     role.Name,
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLRoleResponse{}
+		require.NoError(t, acl.RoleRead(&req, &resp))
+		require.Equal(t, role, resp.Role)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := structs.ACLRoleGetRequest{
+			Datacenter:   "dc1",
+			RoleID:       "6f7fa1e3-3e4b-4a57-8a0b-0bcd8e5c5e5a",
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLRoleResponse{}
+		require.NoError(t, acl.RoleRead(&req, &resp))
+		require.Nil(t, resp.Role)
+	})
+}
+
+func TestACLEndpoint_RoleSet(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	policy, err := upsertTestPolicy(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	acl := ACL{srv: s1}
+	var roleID string
+
+	// Create it, linking the policy by name
+	{
+		req := structs.ACLRoleSetRequest{
+			Datacenter: "dc1",
+			Role: structs.ACLRole{
+				Description: "foobar",
+				Name:        "baz",
+				Policies: []structs.ACLRolePolicyLink{
+					structs.ACLRolePolicyLink{
+						Name: policy.Name,
+					},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		resp := structs.ACLRole{}
+
+		require.NoError(t, acl.RoleSet(&req, &resp))
+		require.NotEmpty(t, resp.ID)
+
+		// Get the role directly to validate that it exists
+		roleResp, err := retrieveTestRole(codec, "root", "dc1", resp.ID)
+		require.NoError(t, err)
+		role := roleResp.Role
+
+		require.Equal(t, "foobar", role.Description)
+		require.Equal(t, "baz", role.Name)
+		require.Len(t, role.Policies, 1)
+		require.Equal(t, policy.ID, role.Policies[0].ID)
+		require.Equal(t, policy.Name, role.Policies[0].Name)
+
+		roleID = role.ID
+	}
+
+	// Update it
+	{
+		req := structs.ACLRoleSetRequest{
+			Datacenter: "dc1",
+			Role: structs.ACLRole{
+				ID:          roleID,
+				Description: "bat",
+				Name:        "bar",
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		resp := structs.ACLRole{}
+
+		require.NoError(t, acl.RoleSet(&req, &resp))
+
+		roleResp, err := retrieveTestRole(codec, "root", "dc1", roleID)
+		require.NoError(t, err)
+		role := roleResp.Role
+
+		require.Equal(t, "bat", role.Description)
+		require.Equal(t, "bar", role.Name)
+		require.Len(t, role.Policies, 0)
+	}
+
+	// Update it with a service identity
+	{
+		req := structs.ACLRoleSetRequest{
+			Datacenter: "dc1",
+			Role: structs.ACLRole{
+				ID:   roleID,
+				Name: "bar",
+				ServiceIdentities: []*structs.ACLServiceIdentity{
+					&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		resp := structs.ACLRole{}
+
+		require.NoError(t, acl.RoleSet(&req, &resp))
+
+		roleResp, err := retrieveTestRole(codec, "root", "dc1", roleID)
+		require.NoError(t, err)
+		require.Equal(t, []*structs.ACLServiceIdentity{
+			&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+		}, roleResp.Role.ServiceIdentities)
+	}
+
+	// Invalid requests
+	for name, role := range map[string]structs.ACLRole{
+		"missing name":   structs.ACLRole{},
+		"invalid name":   structs.ACLRole{Name: "no spaces"},
+		"duplicate name": structs.ACLRole{Name: "bar"},
+		"unknown id":     structs.ACLRole{ID: "6f7fa1e3-3e4b-4a57-8a0b-0bcd8e5c5e5a", Name: "unknown"},
+		"unknown policy": structs.ACLRole{
+			Name:     "unknown-policy",
+			Policies: []structs.ACLRolePolicyLink{structs.ACLRolePolicyLink{Name: "not-found"}},
+		},
+		"invalid service identity": structs.ACLRole{
+			Name:              "invalid-service-identity",
+			ServiceIdentities: []*structs.ACLServiceIdentity{&structs.ACLServiceIdentity{ServiceName: "-web"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := structs.ACLRoleSetRequest{
+				Datacenter:   "dc1",
+				Role:         role,
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+			resp := structs.ACLRole{}
+			require.Error(t, acl.RoleSet(&req, &resp))
+		})
+	}
+}
+
+func TestACLEndpoint_RoleDelete(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	existingRole, err := upsertTestRole(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	acl := ACL{srv: s1}
+
+	req := structs.ACLRoleDeleteRequest{
+		Datacenter:   "dc1",
+		RoleID:       existingRole.ID,
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+
+	var resp string
+
+	require.NoError(t, acl.RoleDelete(&req, &resp))
+	require.Equal(t, existingRole.Name, resp)
+
+	// Make sure the role is gone
+	roleResp, err := retrieveTestRole(codec, "root", "dc1", existingRole.ID)
+	require.NoError(t, err)
+	require.Nil(t, roleResp.Role)
+}
+
+func TestACLEndpoint_RoleList(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	policy, err := upsertTestPolicy(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	r1, err := upsertTestRole(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	arg := structs.ACLRoleSetRequest{
+		Datacenter: "dc1",
+		Role: structs.ACLRole{
+			Name:   "with-policy",
+			Labels: map[string]string{"team": "payments"},
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{ID: policy.ID},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var r2 structs.ACLRole
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.RoleSet", &arg, &r2))
+
+	acl := ACL{srv: s1}
+
+	t.Run("all", func(t *testing.T) {
+		req := structs.ACLRoleListRequest{
+			Datacenter:   "dc1",
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLRoleListResponse{}
+		require.NoError(t, acl.RoleList(&req, &resp))
+
+		var retrieved []string
+		for _, v := range resp.Roles {
+			retrieved = append(retrieved, v.ID)
+		}
+		require.ElementsMatch(t, []string{r1.ID, r2.ID}, retrieved)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		req := structs.ACLRoleListRequest{
+			Datacenter:   "dc1",
+			Policy:       policy.ID,
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLRoleListResponse{}
+		require.NoError(t, acl.RoleList(&req, &resp))
+		require.Len(t, resp.Roles, 1)
+		require.Equal(t, r2.ID, resp.Roles[0].ID)
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		req := structs.ACLRoleListRequest{
+			Datacenter:   "dc1",
+			Labels:       map[string]string{"team": "payments"},
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLRoleListResponse{}
+		require.NoError(t, acl.RoleList(&req, &resp))
+		require.Len(t, resp.Roles, 1)
+		require.Equal(t, r2.ID, resp.Roles[0].ID)
+	})
+}
+
+func TestACLEndpoint_RoleResolve(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	policy, err := upsertTestPolicy(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	r1, err := upsertTestRole(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	r2, err := upsertTestRole(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	acl := ACL{srv: s1}
+
+	// Assign the roles to a token, the second one by name
+	tokenUpsertReq := structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			Policies: []structs.ACLTokenPolicyLink{
+				structs.ACLTokenPolicyLink{
+					ID: policy.ID,
+				},
+			},
+			Roles: []structs.ACLTokenRoleLink{
+				structs.ACLTokenRoleLink{
+					ID: r1.ID,
+				},
+				structs.ACLTokenRoleLink{
+					Name: r2.Name,
+				},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	token := structs.ACLToken{}
+	require.NoError(t, acl.TokenSet(&tokenUpsertReq, &token))
+	require.NotEmpty(t, token.SecretID)
+	require.Len(t, token.Roles, 2)
+	require.Equal(t, r2.ID, token.Roles[1].ID)
+
+	resp := structs.ACLRoleBatchResponse{}
+	req := structs.ACLRoleBatchGetRequest{
+		Datacenter:   "dc1",
+		RoleIDs:      []string{r1.ID, r2.ID},
+		QueryOptions: structs.QueryOptions{Token: token.SecretID},
+	}
+	require.NoError(t, acl.RoleResolve(&req, &resp))
+
+	var retrieved []string
+	for _, v := range resp.Roles {
+		retrieved = append(retrieved, v.ID)
+	}
+	require.EqualValues(t, []string{r1.ID, r2.ID}, retrieved)
+
+	// roles not linked to the token are refused
+	other, err := upsertTestRole(codec, "root", "dc1")
+	require.NoError(t, err)
+
+	req.RoleIDs = []string{other.ID}
+	resp = structs.ACLRoleBatchResponse{}
+	require.Error(t, acl.RoleResolve(&req, &resp))
+}
+
+func TestACLEndpoint_Authorize(t *testing.T) {
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+		c.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	policyReq := structs.ACLPolicySetRequest{
+		Datacenter: "dc1",
+		Policy: structs.ACLPolicy{
+			Name:  "kv-writer",
+			Rules: `key_prefix "foo/" { policy = "write" }`,
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var policy structs.ACLPolicy
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.PolicySet", &policyReq, &policy))
+
+	tokenReq := structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			Policies: []structs.ACLTokenPolicyLink{{ID: policy.ID}},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var token structs.ACLToken
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TokenSet", &tokenReq, &token))
+
+	endpoint := ACL{srv: s1}
+
+	t.Run("allowed by rule", func(t *testing.T) {
+		req := structs.ACLAuthorizeRequest{
+			Datacenter:   "dc1",
+			AccessorID:   token.AccessorID,
+			Resource:     "key",
+			Segment:      "foo/bar",
+			Access:       "write",
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLAuthorizeResponse{}
+
+		require.NoError(t, endpoint.Authorize(&req, &resp))
+		require.True(t, resp.Authorization.Allow)
+		require.Equal(t, `key_prefix "foo/" { policy = "write" }`, resp.Authorization.Rule)
+		require.Equal(t, "deny", resp.Authorization.DefaultPolicy)
+	})
+
+	t.Run("denied by default policy", func(t *testing.T) {
+		req := structs.ACLAuthorizeRequest{
+			Datacenter:   "dc1",
+			Resource:     "key",
+			Segment:      "bar",
+			Access:       "read",
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		resp := structs.ACLAuthorizeResponse{}
+
+		require.NoError(t, endpoint.Authorize(&req, &resp))
+		require.False(t, resp.Authorization.Allow)
+		require.Empty(t, resp.Authorization.Rule)
+		require.Equal(t, "deny", resp.Authorization.DefaultPolicy)
+	})
+
+	t.Run("other tokens require acl read", func(t *testing.T) {
+		req := structs.ACLAuthorizeRequest{
+			Datacenter:   "dc1",
+			AccessorID:   token.AccessorID,
+			Resource:     "key",
+			Segment:      "foo/bar",
+			Access:       "read",
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		resp := structs.ACLAuthorizeResponse{}
+
+		err := endpoint.Authorize(&req, &resp)
+		require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		fakeID, err := uuid.GenerateUUID()
+		require.NoError(t, err)
+
+		req := structs.ACLAuthorizeRequest{
+			Datacenter:   "dc1",
+			AccessorID:   fakeID,
+			Resource:     "key",
+			Segment:      "foo/bar",
+			Access:       "read",
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLAuthorizeResponse{}
+
+		err = endpoint.Authorize(&req, &resp)
+		require.True(t, acl.IsErrNotFound(err), "err: %v", err)
+	})
+
+	t.Run("invalid resource", func(t *testing.T) {
+		req := structs.ACLAuthorizeRequest{
+			Datacenter:   "dc1",
+			Resource:     "bogus",
+			Access:       "read",
+			QueryOptions: structs.QueryOptions{Token: "root"},
+		}
+		resp := structs.ACLAuthorizeResponse{}
+
+		require.EqualError(t, endpoint.Authorize(&req, &resp), `Invalid resource "bogus"`)
+	})
+}
+
 // upsertTestToken creates a token for testing purposes
 func upsertTestToken(codec rpc.ClientCodec, masterToken string, datacenter string) (*structs.ACLToken, error) {
 	arg := structs.ACLTokenSetRequest{
@@ -1517,6 +2524,35 @@ func upsertTestToken(codec rpc.ClientCodec, masterToken string, datacenter strin
 	return &out, nil
 }
 
+// upsertTestACLReadToken creates a token limited to acl:read for testing
+// purposes
+func upsertTestACLReadToken(t *testing.T, codec rpc.ClientCodec, masterToken string, datacenter string) *structs.ACLToken {
+	policyUnq, err := uuid.GenerateUUID()
+	require.NoError(t, err)
+
+	policyArg := structs.ACLPolicySetRequest{
+		Datacenter: datacenter,
+		Policy: structs.ACLPolicy{
+			Name:  "acl-read-" + policyUnq,
+			Rules: `acl = "read"`,
+		},
+		WriteRequest: structs.WriteRequest{Token: masterToken},
+	}
+	var policy structs.ACLPolicy
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.PolicySet", &policyArg, &policy))
+
+	tokenArg := structs.ACLTokenSetRequest{
+		Datacenter: datacenter,
+		ACLToken: structs.ACLToken{
+			Policies: []structs.ACLTokenPolicyLink{{ID: policy.ID}},
+		},
+		WriteRequest: structs.WriteRequest{Token: masterToken},
+	}
+	var token structs.ACLToken
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TokenSet", &tokenArg, &token))
+	return &token
+}
+
 // retrieveTestToken returns a policy for testing purposes
 func retrieveTestToken(codec rpc.ClientCodec, masterToken string, datacenter string, id string) (*structs.ACLTokenResponse, error) {
 	arg := structs.ACLTokenGetRequest{
@@ -1586,3 +2622,53 @@ func retrieveTestPolicy(codec rpc.ClientCodec, masterToken string, datacenter st
 
 	return &out, nil
 }
+
+// upsertTestRole creates a role for testing purposes
+func upsertTestRole(codec rpc.ClientCodec, masterToken string, datacenter string) (*structs.ACLRole, error) {
+	// Make sure test roles can't collide
+	roleUnq, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	arg := structs.ACLRoleSetRequest{
+		Datacenter: datacenter,
+		Role: structs.ACLRole{
+			Name: fmt.Sprintf("test-role-%s", roleUnq),
+		},
+		WriteRequest: structs.WriteRequest{Token: masterToken},
+	}
+
+	var out structs.ACLRole
+
+	err = msgpackrpc.CallWithCodec(codec, "ACL.RoleSet", &arg, &out)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if out.ID == "" {
+		return nil, fmt.Errorf("ID is nil: %v", out)
+	}
+
+	return &out, nil
+}
+
+// retrieveTestRole returns a role for testing purposes
+func retrieveTestRole(codec rpc.ClientCodec, masterToken string, datacenter string, id string) (*structs.ACLRoleResponse, error) {
+	arg := structs.ACLRoleGetRequest{
+		Datacenter:   datacenter,
+		RoleID:       id,
+		QueryOptions: structs.QueryOptions{Token: masterToken},
+	}
+
+	var out structs.ACLRoleResponse
+
+	err := msgpackrpc.CallWithCodec(codec, "ACL.RoleRead", &arg, &out)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
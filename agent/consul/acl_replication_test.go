@@ -330,9 +330,9 @@ func TestACLReplication_Tokens(t *testing.T) {
 
 	checkSame := func(t *retry.R) error {
 		// only account for global tokens - local tokens shouldn't be replicated
-		index, remote, err := s1.fsm.State().ACLTokenList(nil, false, true, "")
+		index, remote, err := s1.fsm.State().ACLTokenList(nil, false, true, "", "")
 		require.NoError(t, err)
-		_, local, err := s2.fsm.State().ACLTokenList(nil, false, true, "")
+		_, local, err := s2.fsm.State().ACLTokenList(nil, false, true, "", "")
 		require.NoError(t, err)
 
 		require.Len(t, local, len(remote))
@@ -426,7 +426,7 @@ func TestACLReplication_Tokens(t *testing.T) {
 	})
 
 	// verify dc2 local tokens didn't get blown away
-	_, local, err := s2.fsm.State().ACLTokenList(nil, true, false, "")
+	_, local, err := s2.fsm.State().ACLTokenList(nil, true, false, "", "")
 	require.NoError(t, err)
 	require.Len(t, local, 50)
 
@@ -447,6 +447,129 @@ func TestACLReplication_Tokens(t *testing.T) {
 	})
 }
 
+func TestACLReplication_Tokens_PrimaryLeaderFailover(t *testing.T) {
+	t.Parallel()
+
+	primaryConf := func(bootstrap bool) func(c *Config) {
+		return func(c *Config) {
+			c.Datacenter = "dc1"
+			c.Bootstrap = bootstrap
+			c.ACLDatacenter = "dc1"
+			c.ACLsEnabled = true
+			c.ACLMasterToken = "root"
+		}
+	}
+
+	var primaries []*Server
+	for i := 0; i < 3; i++ {
+		dir, s := testServerWithConfig(t, primaryConf(i == 0))
+		defer os.RemoveAll(dir)
+		defer s.Shutdown()
+		primaries = append(primaries, s)
+	}
+	joinLAN(t, primaries[1], primaries[0])
+	joinLAN(t, primaries[2], primaries[0])
+	for _, s := range primaries {
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+	testrpc.WaitForLeader(t, primaries[0].RPC, "dc1")
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc2"
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLTokenReplication = true
+		c.ACLReplicationRate = 100
+		c.ACLReplicationBurst = 100
+		c.ACLReplicationApplyLimit = 1000000
+	})
+	s2.tokens.UpdateReplicationToken("root", tokenStore.TokenSourceConfig)
+	testrpc.WaitForLeader(t, s2.RPC, "dc2")
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	for _, s := range primaries {
+		joinWAN(t, s2, s)
+	}
+	testrpc.WaitForLeader(t, s2.RPC, "dc1")
+
+	createTokens := func(s *Server, prefix string, n int) structs.ACLTokens {
+		var tokens structs.ACLTokens
+		for i := 0; i < n; i++ {
+			arg := structs.ACLTokenSetRequest{
+				Datacenter: "dc1",
+				ACLToken: structs.ACLToken{
+					Description: fmt.Sprintf("%s-%d", prefix, i),
+					Policies: []structs.ACLTokenPolicyLink{
+						structs.ACLTokenPolicyLink{
+							ID: structs.ACLPolicyGlobalManagementID,
+						},
+					},
+				},
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+			var token structs.ACLToken
+			require.NoError(t, s.RPC("ACL.TokenSet", &arg, &token))
+			tokens = append(tokens, &token)
+		}
+		return tokens
+	}
+
+	checkSame := func(r *retry.R, primary *Server) {
+		_, remote, err := primary.fsm.State().ACLTokenList(nil, false, true, "", "")
+		require.NoError(r, err)
+		_, local, err := s2.fsm.State().ACLTokenList(nil, false, true, "", "")
+		require.NoError(r, err)
+
+		if len(local) != len(remote) {
+			r.Fatalf("replica has %d tokens, primary has %d", len(local), len(remote))
+		}
+		for i, token := range remote {
+			require.Equal(r, token.Hash, local[i].Hash)
+		}
+	}
+
+	before := createTokens(primaries[0], "before", 20)
+	retry.Run(t, func(r *retry.R) { checkSame(r, primaries[0]) })
+
+	// Kill the primary leader. Replication has to carry on against
+	// whichever server takes over.
+	var remain []*Server
+	for _, s := range primaries {
+		if s.IsLeader() {
+			s.Shutdown()
+			continue
+		}
+		remain = append(remain, s)
+	}
+	require.Len(t, remain, 2)
+	testrpc.WaitForLeader(t, remain[0].RPC, "dc1")
+
+	createTokens(remain[0], "after", 20)
+	for _, token := range before[:10] {
+		arg := structs.ACLTokenDeleteRequest{
+			Datacenter:   "dc1",
+			TokenID:      token.AccessorID,
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		var dontCare string
+		require.NoError(t, remain[0].RPC("ACL.TokenDelete", &arg, &dontCare))
+	}
+
+	// The primary server handling the writes may itself still be catching up
+	// so the deletions are checked together with convergence.
+	retry.Run(t, func(r *retry.R) {
+		for _, token := range before[:10] {
+			_, replicated, err := s2.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID)
+			require.NoError(r, err)
+			if replicated != nil {
+				r.Fatalf("deleted token %s is still replicated", token.AccessorID)
+			}
+		}
+		checkSame(r, remain[0])
+	})
+}
+
 func TestACLReplication_Policies(t *testing.T) {
 	t.Parallel()
 	dir1, s1 := testServerWithConfig(t, func(c *Config) {
@@ -513,7 +636,7 @@ func TestACLReplication_Policies(t *testing.T) {
 		status = s2.aclReplicationStatus
 		s2.aclReplicationStatusLock.RUnlock()
 		if !status.Enabled || !status.Running ||
-			status.ReplicationType != structs.ACLReplicatePolicies ||
+			status.ReplicationType != structs.ACLReplicateRoles ||
 			status.ReplicatedIndex != index ||
 			status.SourceDatacenter != "dc1" {
 			return fmt.Errorf("ACL replication status differs")
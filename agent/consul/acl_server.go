@@ -11,8 +11,8 @@ import (
 var serverACLCacheConfig *structs.ACLCachesConfig = &structs.ACLCachesConfig{
 	// The server's ACL caching has a few underlying assumptions:
 	//
-	// 1 - All policies can be resolved locally. Hence we do not cache any
-	//     unparsed policies as we have memdb for that.
+	// 1 - All policies and roles can be resolved locally. Hence we do not
+	//     cache any unparsed policies or roles as we have memdb for that.
 	// 2 - While there could be many identities being used within a DC the
 	//     number of distinct policies and combined multi-policy authorizers
 	//     will be much less.
@@ -21,10 +21,13 @@ var serverACLCacheConfig *structs.ACLCachesConfig = &structs.ACLCachesConfig{
 	//     cases resolving the tokens from memdb will avoid the cache
 	//     entirely
 	//
-	Identities:     10 * 1024,
-	Policies:       0,
-	ParsedPolicies: 512,
-	Authorizers:    1024,
+	Identities:          10 * 1024,
+	Policies:            0,
+	ParsedPolicies:      512,
+	Authorizers:         1024,
+	Roles:               0,
+	IdentitiesBytes:     32 * 1024 * 1024,
+	ParsedPoliciesBytes: 16 * 1024 * 1024,
 }
 
 func (s *Server) checkTokenUUID(id string) (bool, error) {
@@ -55,6 +58,17 @@ func (s *Server) checkPolicyUUID(id string) (bool, error) {
 	return !structs.ACLIDReserved(id), nil
 }
 
+func (s *Server) checkRoleUUID(id string) (bool, error) {
+	state := s.fsm.State()
+	if _, role, err := state.ACLRoleGetByID(nil, id); err != nil {
+		return false, err
+	} else if role != nil {
+		return false, nil
+	}
+
+	return !structs.ACLIDReserved(id), nil
+}
+
 func (s *Server) updateACLAdvertisement() {
 	// One thing to note is that once in new ACL mode the server will
 	// never transition to legacy ACL mode. This is not currently a
@@ -166,6 +180,19 @@ func (s *Server) ResolvePolicyFromID(policyID string) (bool, *structs.ACLPolicy,
 	return s.InACLDatacenter() || index > 0, policy, acl.ErrNotFound
 }
 
+func (s *Server) ResolveRoleFromID(roleID string) (bool, *structs.ACLRole, error) {
+	index, role, err := s.fsm.State().ACLRoleGetByID(nil, roleID)
+	if err != nil {
+		return true, nil, err
+	} else if role != nil {
+		return true, role, nil
+	}
+
+	// Roles are replicated alongside policies so the same reasoning about
+	// allowing remote resolution before anything was replicated applies.
+	return s.InACLDatacenter() || index > 0, role, acl.ErrNotFound
+}
+
 func (s *Server) ResolveToken(token string) (acl.Authorizer, error) {
 	return s.acls.ResolveToken(token)
 }
@@ -142,6 +142,55 @@ func testIdentityForToken(token string) (bool, structs.ACLIdentity, error) {
 				},
 			},
 		}, nil
+	case "found-role":
+		return true, &structs.ACLToken{
+			AccessorID: "5f57c1f6-6a89-4186-9445-531b316e01df",
+			SecretID:   "a1a54629-5050-4d17-8a4e-560d2423f835",
+			Roles: []structs.ACLTokenRoleLink{
+				structs.ACLTokenRoleLink{
+					ID: "found",
+				},
+			},
+		}, nil
+	case "found-policy-and-role":
+		return true, &structs.ACLToken{
+			AccessorID: "5f57c1f6-6a89-4186-9445-531b316e01df",
+			SecretID:   "a1a54629-5050-4d17-8a4e-560d2423f835",
+			Policies: []structs.ACLTokenPolicyLink{
+				structs.ACLTokenPolicyLink{
+					ID: "node-wr",
+				},
+			},
+			Roles: []structs.ACLTokenRoleLink{
+				structs.ACLTokenRoleLink{
+					ID: "missing-policy",
+				},
+			},
+		}, nil
+	case "found-service-identity":
+		return true, &structs.ACLToken{
+			AccessorID: "5f57c1f6-6a89-4186-9445-531b316e01df",
+			SecretID:   "a1a54629-5050-4d17-8a4e-560d2423f835",
+			ServiceIdentities: []*structs.ACLServiceIdentity{
+				&structs.ACLServiceIdentity{
+					ServiceName: "service1",
+				},
+				&structs.ACLServiceIdentity{
+					ServiceName: "service3",
+					Datacenters: []string{"dc2"},
+				},
+			},
+		}, nil
+	case "found-role-service-identity":
+		return true, &structs.ACLToken{
+			AccessorID: "5f57c1f6-6a89-4186-9445-531b316e01df",
+			SecretID:   "a1a54629-5050-4d17-8a4e-560d2423f835",
+			Roles: []structs.ACLTokenRoleLink{
+				structs.ACLTokenRoleLink{
+					ID: "service-identity",
+				},
+			},
+		}, nil
 	case anonymousToken:
 		return true, &structs.ACLToken{
 			AccessorID: "00000000-0000-0000-0000-000000000002",
@@ -202,6 +251,55 @@ func testPolicyForID(policyID string) (bool, *structs.ACLPolicy, error) {
 	}
 }
 
+func testRoleForID(roleID string) (bool, *structs.ACLRole, error) {
+	switch roleID {
+	case "found":
+		return true, &structs.ACLRole{
+			ID:          "found",
+			Name:        "found",
+			Description: "found",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: "node-wr",
+				},
+				structs.ACLRolePolicyLink{
+					ID: "dc2-key-wr",
+				},
+			},
+			RaftIndex: structs.RaftIndex{CreateIndex: 1, ModifyIndex: 2},
+		}, nil
+	case "missing-policy":
+		return true, &structs.ACLRole{
+			ID:          "missing-policy",
+			Name:        "missing-policy",
+			Description: "missing-policy",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: "not-found",
+				},
+				structs.ACLRolePolicyLink{
+					ID: "acl-ro",
+				},
+			},
+			RaftIndex: structs.RaftIndex{CreateIndex: 1, ModifyIndex: 2},
+		}, nil
+	case "service-identity":
+		return true, &structs.ACLRole{
+			ID:          "service-identity",
+			Name:        "service-identity",
+			Description: "service-identity",
+			ServiceIdentities: []*structs.ACLServiceIdentity{
+				&structs.ACLServiceIdentity{
+					ServiceName: "service2",
+				},
+			},
+			RaftIndex: structs.RaftIndex{CreateIndex: 1, ModifyIndex: 2},
+		}, nil
+	default:
+		return true, nil, acl.ErrNotFound
+	}
+}
+
 // ACLResolverTestDelegate is used to test
 // the ACLResolver without running Agents
 type ACLResolverTestDelegate struct {
@@ -210,9 +308,11 @@ type ACLResolverTestDelegate struct {
 	legacy          bool
 	localTokens     bool
 	localPolicies   bool
+	localRoles      bool
 	getPolicyFn     func(*structs.ACLPolicyResolveLegacyRequest, *structs.ACLPolicyResolveLegacyResponse) error
 	tokenReadFn     func(*structs.ACLTokenGetRequest, *structs.ACLTokenResponse) error
 	policyResolveFn func(*structs.ACLPolicyBatchGetRequest, *structs.ACLPolicyBatchResponse) error
+	roleResolveFn   func(*structs.ACLRoleBatchGetRequest, *structs.ACLRoleBatchResponse) error
 }
 
 func (d *ACLResolverTestDelegate) ACLsEnabled() bool {
@@ -243,6 +343,14 @@ func (d *ACLResolverTestDelegate) ResolvePolicyFromID(policyID string) (bool, *s
 	return testPolicyForID(policyID)
 }
 
+func (d *ACLResolverTestDelegate) ResolveRoleFromID(roleID string) (bool, *structs.ACLRole, error) {
+	if !d.localRoles {
+		return false, nil, nil
+	}
+
+	return testRoleForID(roleID)
+}
+
 func (d *ACLResolverTestDelegate) RPC(method string, args interface{}, reply interface{}) error {
 	switch method {
 	case "ACL.GetPolicy":
@@ -260,6 +368,11 @@ func (d *ACLResolverTestDelegate) RPC(method string, args interface{}, reply int
 			return d.policyResolveFn(args.(*structs.ACLPolicyBatchGetRequest), reply.(*structs.ACLPolicyBatchResponse))
 		}
 		panic("Bad Test Implmentation: should provide a policyResolveFn to the ACLResolverTestDelegate")
+	case "ACL.RoleResolve":
+		if d.roleResolveFn != nil {
+			return d.roleResolveFn(args.(*structs.ACLRoleBatchGetRequest), reply.(*structs.ACLRoleBatchResponse))
+		}
+		panic("Bad Test Implmentation: should provide a roleResolveFn to the ACLResolverTestDelegate")
 	}
 	panic("Bad Test Implementation: Was the ACLResolver updated to use new RPC methods")
 }
@@ -275,6 +388,7 @@ func newTestACLResolver(t *testing.T, delegate ACLResolverDelegate, cb func(*ACL
 			Identities:     4,
 			Policies:       4,
 			ParsedPolicies: 4,
+			Roles:          4,
 			Authorizers:    4,
 		},
 		AutoDisable: true,
@@ -1066,6 +1180,7 @@ func TestACLResolver_LocalTokensAndPolicies(t *testing.T) {
 		legacy:        false,
 		localTokens:   true,
 		localPolicies: true,
+		localRoles:    true,
 		// No need to provide any of the RPC callbacks
 	}
 	r := newTestACLResolver(t, delegate, nil)
@@ -1093,6 +1208,47 @@ func TestACLResolver_LocalTokensAndPolicies(t *testing.T) {
 		require.True(t, authz.NodeWrite("foo", nil))
 	})
 
+	t.Run("Role", func(t *testing.T) {
+		authz, err := r.ResolveToken("found-role")
+		require.NotNil(t, authz)
+		require.NoError(t, err)
+		require.False(t, authz.ACLRead())
+		require.True(t, authz.NodeWrite("foo", nil))
+	})
+
+	t.Run("Policy and Role", func(t *testing.T) {
+		authz, err := r.ResolveToken("found-policy-and-role")
+		require.NotNil(t, authz)
+		require.NoError(t, err)
+		require.True(t, authz.ACLRead())
+		require.True(t, authz.NodeWrite("foo", nil))
+	})
+
+	t.Run("Service Identity", func(t *testing.T) {
+		authz, err := r.ResolveToken("found-service-identity")
+		require.NotNil(t, authz)
+		require.NoError(t, err)
+		require.False(t, authz.ACLRead())
+		require.True(t, authz.ServiceWrite("service1", nil))
+		require.True(t, authz.ServiceWrite("service1-sidecar-proxy", nil))
+		require.True(t, authz.IntentionRead("service1"))
+		require.False(t, authz.IntentionWrite("service1"))
+		require.False(t, authz.ServiceWrite("other", nil))
+		require.True(t, authz.ServiceRead("other"))
+		require.True(t, authz.NodeRead("foo"))
+		require.False(t, authz.NodeWrite("foo", nil))
+		// only valid in dc2
+		require.False(t, authz.ServiceWrite("service3", nil))
+	})
+
+	t.Run("Role Service Identity", func(t *testing.T) {
+		authz, err := r.ResolveToken("found-role-service-identity")
+		require.NotNil(t, authz)
+		require.NoError(t, err)
+		require.True(t, authz.ServiceWrite("service2", nil))
+		require.False(t, authz.ServiceWrite("service1", nil))
+	})
+
 	t.Run("Anonymous", func(t *testing.T) {
 		authz, err := r.ResolveToken("")
 		require.NotNil(t, authz)
@@ -1134,6 +1290,15 @@ func TestACLResolver_LocalPolicies(t *testing.T) {
 			}
 			return err
 		},
+		roleResolveFn: func(args *structs.ACLRoleBatchGetRequest, reply *structs.ACLRoleBatchResponse) error {
+			for _, roleID := range args.RoleIDs {
+				_, role, _ := testRoleForID(roleID)
+				if role != nil {
+					reply.Roles = append(reply.Roles, role)
+				}
+			}
+			return nil
+		},
 	}
 	r := newTestACLResolver(t, delegate, nil)
 
@@ -1160,6 +1325,22 @@ func TestACLResolver_LocalPolicies(t *testing.T) {
 		require.True(t, authz.NodeWrite("foo", nil))
 	})
 
+	t.Run("Role", func(t *testing.T) {
+		authz, err := r.ResolveToken("found-role")
+		require.NotNil(t, authz)
+		require.NoError(t, err)
+		require.False(t, authz.ACLRead())
+		require.True(t, authz.NodeWrite("foo", nil))
+	})
+
+	t.Run("Policy and Role", func(t *testing.T) {
+		authz, err := r.ResolveToken("found-policy-and-role")
+		require.NotNil(t, authz)
+		require.NoError(t, err)
+		require.True(t, authz.ACLRead())
+		require.True(t, authz.NodeWrite("foo", nil))
+	})
+
 	t.Run("Anonymous", func(t *testing.T) {
 		authz, err := r.ResolveToken("")
 		require.NotNil(t, authz)
@@ -1452,6 +1633,28 @@ func TestACLResolver_Legacy(t *testing.T) {
 
 /*
 
+func TestACL_dedupeServiceIdentities(t *testing.T) {
+	t.Parallel()
+
+	in := []*structs.ACLServiceIdentity{
+		&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+		&structs.ACLServiceIdentity{ServiceName: "db", Datacenters: []string{"dc1"}},
+		&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc2", "dc1"}},
+		&structs.ACLServiceIdentity{ServiceName: "db"},
+		&structs.ACLServiceIdentity{ServiceName: "db", Datacenters: []string{"dc3"}},
+	}
+
+	out := dedupeServiceIdentities(in)
+	require.Equal(t, []*structs.ACLServiceIdentity{
+		&structs.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1", "dc2"}},
+		&structs.ACLServiceIdentity{ServiceName: "db"},
+	}, out)
+
+	// the input is left untouched
+	require.Equal(t, []string{"dc1"}, in[0].Datacenters)
+	require.Equal(t, []string{"dc1"}, in[1].Datacenters)
+}
+
 func TestACL_Replication(t *testing.T) {
 	t.Parallel()
 	aclExtendPolicies := []string{"extend-cache", "async-cache"} //"async-cache"
@@ -2443,6 +2646,47 @@ func TestACL_redactTokenSecrets(t *testing.T) {
 	require.Equal(t, redactedToken, tokens[0].SecretID)
 }
 
+func TestACL_redactTokenResponses(t *testing.T) {
+	t.Parallel()
+	delegate := &ACLResolverTestDelegate{
+		enabled:       true,
+		datacenter:    "dc1",
+		legacy:        false,
+		localTokens:   true,
+		localPolicies: true,
+		// No need to provide any of the RPC callbacks
+	}
+	r := newTestACLResolver(t, delegate, nil)
+
+	token := &structs.ACLToken{
+		AccessorID: "6a5e25b3-28f2-4085-9012-c3fb754314d1",
+		SecretID:   "6a5e25b3-28f2-4085-9012-c3fb754314d1",
+	}
+
+	resp := structs.ACLTokenResponse{Token: token}
+	require.NoError(t, r.filterACL("acl-wr", &resp))
+	require.False(t, resp.Redacted)
+	require.Equal(t, token.SecretID, resp.Token.SecretID)
+
+	require.NoError(t, r.filterACL("acl-ro", &resp))
+	require.True(t, resp.Redacted)
+	require.Equal(t, redactedToken, resp.Token.SecretID)
+
+	batch := structs.ACLTokenBatchResponse{Tokens: []*structs.ACLToken{token}}
+	require.NoError(t, r.filterACL("acl-ro", &batch))
+	require.True(t, batch.Redacted)
+	require.Equal(t, redactedToken, batch.Tokens[0].SecretID)
+
+	// The token held by the caller must not be modified.
+	require.Equal(t, "6a5e25b3-28f2-4085-9012-c3fb754314d1", token.SecretID)
+
+	// A missing token is left alone.
+	empty := structs.ACLTokenResponse{}
+	require.NoError(t, r.filterACL("acl-ro", &empty))
+	require.False(t, empty.Redacted)
+	require.Nil(t, empty.Token)
+}
+
 func TestACL_filterPreparedQueries(t *testing.T) {
 	t.Parallel()
 	queries := structs.PreparedQueries{
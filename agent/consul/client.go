@@ -425,5 +425,6 @@ func (c *Client) GetLANCoordinate() (lib.CoordinateSet, error) {
 // relevant configuration information
 func (c *Client) ReloadConfig(config *Config) error {
 	c.rpcLimiter.Store(rate.NewLimiter(config.RPCRate, config.RPCMaxBurst))
+	c.acls.reloadCacheTTLs(config.ACLTokenTTL, config.ACLPolicyTTL)
 	return nil
 }
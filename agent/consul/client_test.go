@@ -730,4 +730,12 @@ func TestClient_Reload(t *testing.T) {
 	limiter = c.rpcLimiter.Load().(*rate.Limiter)
 	require.Equal(t, rate.Limit(1000), limiter.Limit())
 	require.Equal(t, 10000, limiter.Burst())
+
+	c.config.ACLTokenTTL = 5 * time.Minute
+	c.config.ACLPolicyTTL = 2 * time.Minute
+
+	require.NoError(t, c.ReloadConfig(c.config))
+	tokenTTL, policyTTL := c.acls.cacheTTLs()
+	require.Equal(t, 5*time.Minute, tokenTTL)
+	require.Equal(t, 2*time.Minute, policyTTL)
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
@@ -287,6 +288,16 @@ type Config struct {
 	// by default in Consul 1.0 and later.
 	ACLEnableKeyListPolicy bool
 
+	// ACLPolicyNamePattern, when set, is matched against the name of every
+	// ACL policy written through the ACL.PolicySet endpoint. Writes with
+	// non-matching names are rejected.
+	ACLPolicyNamePattern *regexp.Regexp
+
+	// ACLRoleNamePattern, when set, is matched against the name of every
+	// ACL role written through the ACL.RoleSet endpoint. Writes with
+	// non-matching names are rejected.
+	ACLRoleNamePattern *regexp.Regexp
+
 	// TombstoneTTL is used to control how long KV tombstones are retained.
 	// This provides a window of time where the X-Consul-Index is monotonic.
 	// Outside this window, the index may not be monotonic. This is a result
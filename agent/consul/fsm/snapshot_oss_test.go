@@ -96,6 +96,19 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	policy.SetHash(true)
 	require.NoError(fsm.state.ACLPolicySet(1, &policy))
 
+	role := structs.ACLRole{
+		ID:          "86dedd19-8fae-4594-8294-4e6948a81f9a",
+		Name:        "some-role",
+		Description: "test snapshot role",
+		Policies: []structs.ACLRolePolicyLink{
+			{
+				ID: structs.ACLPolicyGlobalManagementID,
+			},
+		},
+	}
+	role.SetHash(true)
+	require.NoError(fsm.state.ACLRoleSet(1, &role))
+
 	token := &structs.ACLToken{
 		AccessorID:  "30fca056-9fbb-4455-b94a-bf0e2bc575d6",
 		SecretID:    "cbe1c6fd-d865-4034-9d6d-64fef7fb46a9",
@@ -330,6 +343,12 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	require.NoError(err)
 	require.Equal(policy.Name, policy2.Name)
 
+	// Verify ACL Role is restored
+	_, role2, err := fsm2.state.ACLRoleGetByID(nil, role.ID)
+	require.NoError(err)
+	require.Equal(role.Name, role2.Name)
+	require.Len(role2.Policies, 1)
+
 	// Verify tombstones are restored
 	func() {
 		snap := fsm2.state.Snapshot()
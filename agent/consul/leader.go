@@ -37,6 +37,10 @@ var (
 	// caRootPruneInterval is how often we check for stale CARoots to remove.
 	caRootPruneInterval = time.Hour
 
+	// aclTokenReapInterval is how often we check for expired ACL tokens to
+	// delete.
+	aclTokenReapInterval = time.Minute
+
 	// minAutopilotVersion is the minimum Consul version in which Autopilot features
 	// are supported.
 	minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
@@ -297,6 +301,8 @@ func (s *Server) revokeLeadership() error {
 
 	s.stopACLUpgrade()
 
+	s.stopACLTokenReaping()
+
 	s.resetConsistentReadReady()
 	s.autopilot.Stop()
 	return nil
@@ -577,6 +583,10 @@ func (s *Server) initializeACLs(upgrade bool) error {
 
 	// launch the upgrade go routine to generate accessors for everything
 
+	// Expired tokens have to be reaped by whichever server is the leader, so
+	// this is started every time leadership is established.
+	s.startACLTokenReaping()
+
 	return nil
 }
 
@@ -678,6 +688,105 @@ func (s *Server) stopACLUpgrade() {
 	s.aclUpgradeEnabled = false
 }
 
+// startACLTokenReaping starts a goroutine that periodically deletes expired
+// ACL tokens. Local tokens are reaped by the leader of every datacenter while
+// global tokens are only reaped within the ACL datacenter, from where the
+// deletions are replicated.
+func (s *Server) startACLTokenReaping() {
+	s.aclTokenReapLock.Lock()
+	defer s.aclTokenReapLock.Unlock()
+
+	if s.aclTokenReapEnabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.aclTokenReapCancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(aclTokenReapInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.LocalTokensEnabled() {
+					if _, err := s.reapExpiredACLTokens(true); err != nil {
+						s.logger.Printf("[ERR] acl: error reaping expired local tokens: %v", err)
+					}
+				}
+				if s.InACLDatacenter() {
+					if _, err := s.reapExpiredACLTokens(false); err != nil {
+						s.logger.Printf("[ERR] acl: error reaping expired global tokens: %v", err)
+					}
+				}
+			}
+		}
+	}()
+
+	s.aclTokenReapEnabled = true
+}
+
+func (s *Server) stopACLTokenReaping() {
+	s.aclTokenReapLock.Lock()
+	defer s.aclTokenReapLock.Unlock()
+
+	if !s.aclTokenReapEnabled {
+		return
+	}
+
+	s.aclTokenReapCancel()
+	s.aclTokenReapCancel = nil
+	s.aclTokenReapEnabled = false
+}
+
+// reapExpiredACLTokens deletes the local or global tokens that have expired
+// and returns how many were deleted.
+func (s *Server) reapExpiredACLTokens(local bool) (int, error) {
+	if !s.ACLsEnabled() || s.UseLegacyACLs() {
+		return 0, nil
+	}
+
+	reaped := 0
+	for {
+		tokens, err := s.fsm.State().ACLTokenListExpired(local, time.Now(), aclBatchDeleteSize)
+		if err != nil {
+			return reaped, err
+		}
+		if len(tokens) == 0 {
+			return reaped, nil
+		}
+
+		req := structs.ACLTokenBatchDeleteRequest{
+			TokenIDs: make([]string, 0, len(tokens)),
+		}
+		for _, token := range tokens {
+			req.TokenIDs = append(req.TokenIDs, token.AccessorID)
+		}
+
+		s.logger.Printf("[INFO] acl: deleting %d expired tokens", len(req.TokenIDs))
+		resp, err := s.raftApply(structs.ACLTokenDeleteRequestType, &req)
+		if err != nil {
+			return reaped, fmt.Errorf("failed to apply token expiration deletions: %v", err)
+		}
+		if respErr, ok := resp.(error); ok {
+			return reaped, respErr
+		}
+
+		// Purge the identities from the cache to prevent using them any longer
+		for _, token := range tokens {
+			s.acls.cache.RemoveIdentity(token.SecretID)
+		}
+
+		reaped += len(tokens)
+		if len(tokens) < aclBatchDeleteSize {
+			return reaped, nil
+		}
+	}
+}
+
 func (s *Server) startLegacyACLReplication() {
 	s.aclReplicationLock.Lock()
 	defer s.aclReplicationLock.Unlock()
@@ -782,6 +891,54 @@ func (s *Server) startACLReplication() {
 
 	s.logger.Printf("[INFO] acl: started ACL Policy replication")
 
+	// Roles are needed to resolve tokens so, like policies, they are always
+	// replicated.
+	replicationType = structs.ACLReplicateRoles
+
+	go func() {
+		var failedAttempts uint
+		limiter := rate.NewLimiter(rate.Limit(s.config.ACLReplicationRate), s.config.ACLReplicationBurst)
+
+		var lastRemoteIndex uint64
+		for {
+			if err := limiter.Wait(ctx); err != nil {
+				return
+			}
+
+			if s.tokens.ReplicationToken() == "" {
+				continue
+			}
+
+			index, exit, err := s.replicateACLRoles(lastRemoteIndex, ctx)
+			if exit {
+				return
+			}
+
+			if err != nil {
+				lastRemoteIndex = 0
+				s.updateACLReplicationStatusError()
+				s.logger.Printf("[WARN] consul: ACL role replication error (will retry if still leader): %v", err)
+				if (1 << failedAttempts) < aclReplicationMaxRetryBackoff {
+					failedAttempts++
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After((1 << failedAttempts) * time.Second):
+					// do nothing
+				}
+			} else {
+				lastRemoteIndex = index
+				s.updateACLReplicationStatusRoleIndex(index)
+				s.logger.Printf("[DEBUG] consul: ACL role replication completed through remote index %d", index)
+				failedAttempts = 0
+			}
+		}
+	}()
+
+	s.logger.Printf("[INFO] acl: started ACL Role replication")
+
 	if s.config.ACLTokenReplication {
 		replicationType = structs.ACLReplicateTokens
 
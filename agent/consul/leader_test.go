@@ -1,11 +1,14 @@
 package consul
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
@@ -1003,6 +1006,201 @@ func TestLeader_ACL_Initialization(t *testing.T) {
 	}
 }
 
+// TestLeader_ACL_WritesAcrossLeaderFailover kills the leader while tokens
+// and policies are being written through a follower. Every acknowledged
+// write must survive on the remaining servers, nothing may be stored half
+// written, and the remaining servers must converge on the same ACL state.
+func TestLeader_ACL_WritesAcrossLeaderFailover(t *testing.T) {
+	t.Parallel()
+
+	conf := func(bootstrap bool) func(c *Config) {
+		return func(c *Config) {
+			c.Datacenter = "dc1"
+			c.Bootstrap = bootstrap
+			c.ACLDatacenter = "dc1"
+			c.ACLsEnabled = true
+			c.ACLMasterToken = "root"
+		}
+	}
+
+	dir1, s1 := testServerWithConfig(t, conf(true))
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, conf(false))
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	dir3, s3 := testServerWithConfig(t, conf(false))
+	defer os.RemoveAll(dir3)
+	defer s3.Shutdown()
+	servers := []*Server{s1, s2, s3}
+
+	joinLAN(t, s2, s1)
+	joinLAN(t, s3, s1)
+	for _, s := range servers {
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	var leader, writer *Server
+	for _, s := range servers {
+		if s.IsLeader() {
+			leader = s
+		} else if writer == nil {
+			writer = s
+		}
+	}
+	require.NotNil(t, leader)
+	require.NotNil(t, writer)
+
+	type ack struct {
+		policyID   string
+		accessorID string
+	}
+	var (
+		acksLock sync.Mutex
+		acked    []ack
+	)
+	numAcked := func() int {
+		acksLock.Lock()
+		defer acksLock.Unlock()
+		return len(acked)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	// Write policy and token pairs through the follower until stopped. Errors
+	// are expected while there is no leader and are simply retried.
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			policyReq := structs.ACLPolicySetRequest{
+				Datacenter: "dc1",
+				Policy: structs.ACLPolicy{
+					Name:  fmt.Sprintf("failover-%d", i),
+					Rules: `node_prefix "" { policy = "read" }`,
+				},
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+			var policy structs.ACLPolicy
+			if err := writer.RPC("ACL.PolicySet", &policyReq, &policy); err != nil {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+
+			tokenReq := structs.ACLTokenSetRequest{
+				Datacenter: "dc1",
+				ACLToken: structs.ACLToken{
+					Description: fmt.Sprintf("failover-%d", i),
+					Policies:    []structs.ACLTokenPolicyLink{{ID: policy.ID}},
+				},
+				WriteRequest: structs.WriteRequest{Token: "root"},
+			}
+			var token structs.ACLToken
+			a := ack{policyID: policy.ID}
+			err := writer.RPC("ACL.TokenSet", &tokenReq, &token)
+			if err == nil {
+				a.accessorID = token.AccessorID
+			}
+
+			acksLock.Lock()
+			acked = append(acked, a)
+			acksLock.Unlock()
+
+			if err != nil {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+
+	// Let some writes land, then kill the leader without a graceful leave.
+	retry.Run(t, func(r *retry.R) {
+		if n := numAcked(); n < 5 {
+			r.Fatalf("only %d writes acknowledged", n)
+		}
+	})
+	leader.Shutdown()
+
+	var remain []*Server
+	for _, s := range servers {
+		if s != leader {
+			remain = append(remain, s)
+		}
+	}
+	testrpc.WaitForLeader(t, remain[0].RPC, "dc1")
+
+	// Make sure writes resume against the new leader before stopping.
+	before := numAcked()
+	retry.Run(t, func(r *retry.R) {
+		if numAcked() < before+5 {
+			r.Fatalf("writes have not resumed after failover")
+		}
+	})
+	close(stop)
+	<-done
+
+	for _, s := range remain {
+		retry.Run(t, func(r *retry.R) {
+			state := s.fsm.State()
+			for _, a := range acked {
+				_, policy, err := state.ACLPolicyGetByID(nil, a.policyID)
+				require.NoError(r, err)
+				if policy == nil {
+					r.Fatalf("policy %s missing on %s", a.policyID, s.config.NodeName)
+				}
+
+				if a.accessorID == "" {
+					continue
+				}
+				_, token, err := state.ACLTokenGetByAccessor(nil, a.accessorID)
+				require.NoError(r, err)
+				if token == nil {
+					r.Fatalf("token %s missing on %s", a.accessorID, s.config.NodeName)
+				}
+			}
+		})
+
+		// No stored token may be missing any of the fields set on creation.
+		_, tokens, err := s.fsm.State().ACLTokenList(nil, true, true, "", "")
+		require.NoError(t, err)
+		for _, token := range tokens {
+			require.NotEmpty(t, token.AccessorID)
+			require.NotEmpty(t, token.SecretID)
+			require.NotEmpty(t, token.Hash)
+			require.False(t, token.CreateTime.IsZero(), "token %s has no create time", token.AccessorID)
+		}
+	}
+
+	// The remaining servers must agree on the full token and policy sets.
+	retry.Run(t, func(r *retry.R) {
+		_, tokens0, err := remain[0].fsm.State().ACLTokenList(nil, true, true, "", "")
+		require.NoError(r, err)
+		_, tokens1, err := remain[1].fsm.State().ACLTokenList(nil, true, true, "", "")
+		require.NoError(r, err)
+		if len(tokens0) != len(tokens1) {
+			r.Fatalf("token counts differ: %d != %d", len(tokens0), len(tokens1))
+		}
+		for i := range tokens0 {
+			require.Equal(r, tokens0[i].Hash, tokens1[i].Hash)
+		}
+
+		_, policies0, err := remain[0].fsm.State().ACLPolicyList(nil)
+		require.NoError(r, err)
+		_, policies1, err := remain[1].fsm.State().ACLPolicyList(nil)
+		require.NoError(r, err)
+		if len(policies0) != len(policies1) {
+			r.Fatalf("policy counts differ: %d != %d", len(policies0), len(policies1))
+		}
+	})
+}
+
 func TestLeader_CARootPruning(t *testing.T) {
 	t.Parallel()
 
@@ -1206,3 +1404,156 @@ func TestLeader_ACLUpgrade(t *testing.T) {
 		require.Equal(t, client.ACL.Rules, token.Rules)
 	})
 }
+
+func TestLeader_ACLTokenReaping(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLMasterToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	// Write the tokens directly as the endpoint refuses expiration times
+	// in the past.
+	req := structs.ACLTokenBatchSetRequest{
+		Tokens: structs.ACLTokens{
+			&structs.ACLToken{
+				AccessorID:     "3c4e8aab-6ed7-4a17-9b4a-7f2e1b6c5d01",
+				SecretID:       "a5b6c7d8-1e2f-4a3b-8c4d-5e6f7a8b9c01",
+				ExpirationTime: &past,
+			},
+			&structs.ACLToken{
+				AccessorID:     "4d5f9bbc-7fe0-4b28-8c5b-8a3f2c7d6e02",
+				SecretID:       "b6c7d8e9-2f3a-4b4c-9d5e-6f7a8b9c0d02",
+				ExpirationTime: &past,
+				Local:          true,
+			},
+			&structs.ACLToken{
+				AccessorID:     "5e6a0ccd-8af1-4c39-9d6c-9b4a3d8e7f03",
+				SecretID:       "c7d8e9fa-3a4b-4c5d-8e6f-7a8b9c0d1e03",
+				ExpirationTime: &future,
+			},
+		},
+	}
+	_, err := s1.raftApply(structs.ACLTokenSetRequestType, &req)
+	require.NoError(t, err)
+
+	// Expired tokens can no longer be read or used before they are reaped.
+	tokenResp, err := retrieveTestToken(codec, "root", "dc1", "3c4e8aab-6ed7-4a17-9b4a-7f2e1b6c5d01")
+	require.NoError(t, err)
+	require.Nil(t, tokenResp.Token)
+	_, err = s1.ResolveToken("a5b6c7d8-1e2f-4a3b-8c4d-5e6f7a8b9c01")
+	require.True(t, acl.IsErrNotFound(err))
+
+	reaped, err := s1.reapExpiredACLTokens(false)
+	require.NoError(t, err)
+	require.Equal(t, 1, reaped)
+
+	reaped, err = s1.reapExpiredACLTokens(true)
+	require.NoError(t, err)
+	require.Equal(t, 1, reaped)
+
+	state := s1.fsm.State()
+	for _, id := range []string{"3c4e8aab-6ed7-4a17-9b4a-7f2e1b6c5d01", "4d5f9bbc-7fe0-4b28-8c5b-8a3f2c7d6e02"} {
+		_, token, err := state.ACLTokenGetByAccessor(nil, id)
+		require.NoError(t, err)
+		require.Nil(t, token)
+	}
+	_, token, err := state.ACLTokenGetByAccessor(nil, "5e6a0ccd-8af1-4c39-9d6c-9b4a3d8e7f03")
+	require.NoError(t, err)
+	require.NotNil(t, token)
+
+	// Nothing is left to reap.
+	reaped, err = s1.reapExpiredACLTokens(false)
+	require.NoError(t, err)
+	require.Zero(t, reaped)
+}
+
+func TestLeader_ACLTokenReaping_LeaderFailover(t *testing.T) {
+	t.Parallel()
+
+	aclTokenReapInterval = 100 * time.Millisecond
+
+	conf := func(bootstrap bool) func(c *Config) {
+		return func(c *Config) {
+			c.Datacenter = "dc1"
+			c.Bootstrap = bootstrap
+			c.ACLDatacenter = "dc1"
+			c.ACLsEnabled = true
+			c.ACLMasterToken = "root"
+		}
+	}
+
+	dir1, s1 := testServerWithConfig(t, conf(true))
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, conf(false))
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	dir3, s3 := testServerWithConfig(t, conf(false))
+	defer os.RemoveAll(dir3)
+	defer s3.Shutdown()
+	servers := []*Server{s1, s2, s3}
+
+	joinLAN(t, s2, s1)
+	joinLAN(t, s3, s1)
+	for _, s := range servers {
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	var remain []*Server
+	for _, s := range servers {
+		if s.IsLeader() {
+			s.Shutdown()
+		} else {
+			remain = append(remain, s)
+		}
+	}
+	require.Len(t, remain, 2)
+	testrpc.WaitForLeader(t, remain[0].RPC, "dc1")
+
+	var leader *Server
+	retry.Run(t, func(r *retry.R) {
+		for _, s := range remain {
+			if s.IsLeader() {
+				leader = s
+				return
+			}
+		}
+		r.Fatal("no leader")
+	})
+
+	// The new leader was a follower that had already switched to the new ACLs,
+	// it has to reap expired tokens nonetheless.
+	past := time.Now().Add(-time.Hour)
+	req := structs.ACLTokenBatchSetRequest{
+		Tokens: structs.ACLTokens{
+			&structs.ACLToken{
+				AccessorID:     "6f7b1dde-9b02-4d4a-8e7d-0c5b4e9f8a04",
+				SecretID:       "d8e9fa0b-4b5c-4d6e-9f7a-8b9c0d1e2f04",
+				ExpirationTime: &past,
+			},
+		},
+	}
+	_, err := leader.raftApply(structs.ACLTokenSetRequestType, &req)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		_, token, err := leader.fsm.State().ACLTokenGetByAccessor(nil, "6f7b1dde-9b02-4d4a-8e7d-0c5b4e9f8a04")
+		r.Check(err)
+		if token != nil {
+			r.Fatal("expired token has not been reaped")
+		}
+	})
+}
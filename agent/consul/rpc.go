@@ -411,6 +411,11 @@ RUN_QUERY:
 		// This channel will be closed if a snapshot is restored and the
 		// whole state store is abandoned.
 		ws.Add(state.AbandonCh())
+
+		// This channel will be closed when the server shuts down. Its state
+		// store won't change anymore, so the caller should move on to
+		// another server rather than wait out the query timeout.
+		ws.Add(s.shutdownCh)
 	}
 
 	// Block up to the timeout if we didn't see anything fresh.
@@ -428,13 +433,14 @@ RUN_QUERY:
 	}
 	if err == nil && queryOpts.MinQueryIndex > 0 && queryMeta.Index <= queryOpts.MinQueryIndex {
 		if expired := ws.Watch(timeout.C); !expired {
-			// If a restore may have woken us up then bail out from
-			// the query immediately. This is slightly race-ey since
-			// this might have been interrupted for other reasons,
+			// If a restore or shutdown may have woken us up then bail
+			// out from the query immediately. This is slightly race-ey
+			// since this might have been interrupted for other reasons,
 			// but it's OK to kick it back to the caller in either
 			// case.
 			select {
 			case <-state.AbandonCh():
+			case <-s.shutdownCh:
 			default:
 				goto RUN_QUERY
 			}
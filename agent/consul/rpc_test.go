@@ -282,6 +282,30 @@ func TestRPC_blockingQuery(t *testing.T) {
 			t.Fatalf("bad: %d", calls)
 		}
 	}
+
+	// Perform a query that blocks and gets interrupted when the server shuts
+	// down. This must be the last query since it stops the server.
+	{
+		opts := structs.QueryOptions{
+			MinQueryIndex: 3,
+			MaxQueryTime:  10 * time.Second,
+		}
+		var meta structs.QueryMeta
+		var calls int
+		fn := func(ws memdb.WatchSet, state *state.Store) error {
+			if calls == 0 {
+				meta.Index = 3
+				s.Shutdown()
+			}
+			calls++
+			return nil
+		}
+		t0 := time.Now()
+		require.NoError(s.blockingQuery(&opts, &meta, fn))
+		assert.Equal(1, calls)
+		assert.True(time.Since(t0) < opts.MaxQueryTime,
+			"should not have waited for the query timeout")
+	}
 }
 
 func TestRPC_ReadyForConsistentReads(t *testing.T) {
@@ -970,3 +970,27 @@ func TestServer_RevokeLeadershipIdempotent(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestServer_ReloadConfig(t *testing.T) {
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ACLDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLTokenTTL = 30 * time.Second
+		c.ACLPolicyTTL = 30 * time.Second
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	tokenTTL, policyTTL := s1.acls.cacheTTLs()
+	require.Equal(t, 30*time.Second, tokenTTL)
+	require.Equal(t, 30*time.Second, policyTTL)
+
+	s1.config.ACLTokenTTL = 5 * time.Minute
+	s1.config.ACLPolicyTTL = 2 * time.Minute
+
+	require.NoError(t, s1.ReloadConfig(s1.config))
+	tokenTTL, policyTTL = s1.acls.cacheTTLs()
+	require.Equal(t, 5*time.Minute, tokenTTL)
+	require.Equal(t, 2*time.Minute, policyTTL)
+}
@@ -1,7 +1,9 @@
 package state
 
 import (
+	"encoding/binary"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/go-memdb"
@@ -58,6 +60,101 @@ func (s *TokenPoliciesIndex) PrefixFromArgs(args ...interface{}) ([]byte, error)
 	return val, nil
 }
 
+// TokenRolesIndex indexes tokens by the IDs of the roles they link.
+type TokenRolesIndex struct {
+	TokenPoliciesIndex
+}
+
+func (s *TokenRolesIndex) FromObject(obj interface{}) (bool, [][]byte, error) {
+	token, ok := obj.(*structs.ACLToken)
+	if !ok {
+		return false, nil, fmt.Errorf("object is not an ACLToken")
+	}
+
+	links := token.Roles
+
+	numLinks := len(links)
+	if numLinks == 0 {
+		return false, nil, nil
+	}
+
+	vals := make([][]byte, 0, numLinks)
+	for _, link := range links {
+		vals = append(vals, []byte(link.ID+"\x00"))
+	}
+
+	return true, vals, nil
+}
+
+// RolePoliciesIndex indexes roles by the IDs of the policies they link.
+type RolePoliciesIndex struct {
+	TokenPoliciesIndex
+}
+
+func (s *RolePoliciesIndex) FromObject(obj interface{}) (bool, [][]byte, error) {
+	role, ok := obj.(*structs.ACLRole)
+	if !ok {
+		return false, nil, fmt.Errorf("object is not an ACLRole")
+	}
+
+	links := role.Policies
+
+	numLinks := len(links)
+	if numLinks == 0 {
+		return false, nil, nil
+	}
+
+	vals := make([][]byte, 0, numLinks)
+	for _, link := range links {
+		vals = append(vals, []byte(link.ID+"\x00"))
+	}
+
+	return true, vals, nil
+}
+
+// TokenExpirationIndex indexes tokens that have an expiration time by that
+// time so that they can be iterated in order of expiry. Local and global
+// tokens are kept in separate indexes as they are reaped by different
+// leaders.
+type TokenExpirationIndex struct {
+	LocalFilter bool
+}
+
+func (s *TokenExpirationIndex) encodeTime(t time.Time) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(t.Unix()))
+	return buf
+}
+
+func (s *TokenExpirationIndex) FromObject(obj interface{}) (bool, []byte, error) {
+	token, ok := obj.(*structs.ACLToken)
+	if !ok {
+		return false, nil, fmt.Errorf("object is not an ACLToken")
+	}
+	if s.LocalFilter != token.Local || !token.HasExpirationTime() {
+		return false, nil, nil
+	}
+	if token.ExpirationTime.Unix() < 0 {
+		return false, nil, fmt.Errorf("token expiration time cannot be before the unix epoch: %s", token.ExpirationTime)
+	}
+
+	return true, s.encodeTime(*token.ExpirationTime), nil
+}
+
+func (s *TokenExpirationIndex) FromArgs(args ...interface{}) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("must provide only a single argument")
+	}
+	arg, ok := args[0].(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("argument must be a time.Time: %#v", args[0])
+	}
+	if arg.Unix() < 0 {
+		return nil, fmt.Errorf("argument must not be before the unix epoch: %s", arg)
+	}
+	return s.encodeTime(arg), nil
+}
+
 func tokensTableSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
 		Name: "acl-tokens",
@@ -87,6 +184,12 @@ func tokensTableSchema() *memdb.TableSchema {
 				Unique:       false,
 				Indexer:      &TokenPoliciesIndex{},
 			},
+			"roles": &memdb.IndexSchema{
+				Name:         "roles",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer:      &TokenRolesIndex{},
+			},
 			"local": &memdb.IndexSchema{
 				Name:         "local",
 				AllowMissing: false,
@@ -100,6 +203,18 @@ func tokensTableSchema() *memdb.TableSchema {
 					},
 				},
 			},
+			"expires-global": &memdb.IndexSchema{
+				Name:         "expires-global",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer:      &TokenExpirationIndex{LocalFilter: false},
+			},
+			"expires-local": &memdb.IndexSchema{
+				Name:         "expires-local",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer:      &TokenExpirationIndex{LocalFilter: true},
+			},
 
 			//DEPRECATED (ACL-Legacy-Compat) - This index is only needed while we support upgrading v1 to v2 acls
 			// This table indexes all the ACL tokens that do not have an AccessorID
@@ -146,9 +261,42 @@ func policiesTableSchema() *memdb.TableSchema {
 	}
 }
 
+func rolesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl-roles",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": &memdb.IndexSchema{
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+			"name": &memdb.IndexSchema{
+				Name:         "name",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "Name",
+					Lowercase: true,
+				},
+			},
+			"policies": &memdb.IndexSchema{
+				Name: "policies",
+				// Need to allow missing for roles without policies
+				AllowMissing: true,
+				Unique:       false,
+				Indexer:      &RolePoliciesIndex{},
+			},
+		},
+	}
+}
+
 func init() {
 	registerSchema(tokensTableSchema)
 	registerSchema(policiesTableSchema)
+	registerSchema(rolesTableSchema)
 }
 
 // ACLTokens is used when saving a snapshot
@@ -193,6 +341,26 @@ func (s *Restore) ACLPolicy(policy *structs.ACLPolicy) error {
 	return nil
 }
 
+// ACLRoles is used when saving a snapshot
+func (s *Snapshot) ACLRoles() (memdb.ResultIterator, error) {
+	iter, err := s.tx.Get("acl-roles", "id")
+	if err != nil {
+		return nil, err
+	}
+	return iter, nil
+}
+
+func (s *Restore) ACLRole(role *structs.ACLRole) error {
+	if err := s.tx.Insert("acl-roles", role); err != nil {
+		return fmt.Errorf("failed restoring acl role: %s", err)
+	}
+
+	if err := indexUpdateMaxTxn(s.tx, role.ModifyIndex, "acl-roles"); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+	return nil
+}
+
 // ACLBootstrap is used to perform a one-time ACL bootstrap operation on a
 // cluster to get the first management token.
 func (s *Store) ACLBootstrap(idx, resetIndex uint64, token *structs.ACLToken, legacy bool) error {
@@ -315,6 +483,83 @@ func (s *Store) fixupTokenPolicyLinks(tx *memdb.Txn, original *structs.ACLToken)
 	return token, nil
 }
 
+func (s *Store) resolveTokenRoleLinks(tx *memdb.Txn, token *structs.ACLToken, allowMissing bool) error {
+	for linkIndex, link := range token.Roles {
+		if link.ID != "" {
+			role, err := s.getRoleWithTxn(tx, nil, link.ID, "id")
+
+			if err != nil {
+				return err
+			}
+
+			if role != nil {
+				// the name doesn't matter here
+				token.Roles[linkIndex].Name = role.Name
+			} else if !allowMissing {
+				return fmt.Errorf("No such role with ID: %s", link.ID)
+			}
+		} else {
+			return fmt.Errorf("Encountered a Token with roles linked by Name in the state store")
+		}
+	}
+	return nil
+}
+
+// fixupTokenRoleLinks is the role equivalent of fixupTokenPolicyLinks.
+func (s *Store) fixupTokenRoleLinks(tx *memdb.Txn, original *structs.ACLToken) (*structs.ACLToken, error) {
+	owned := false
+	token := original
+
+	cloneToken := func(t *structs.ACLToken, copyNumLinks int) *structs.ACLToken {
+		clone := *t
+		clone.Roles = make([]structs.ACLTokenRoleLink, copyNumLinks)
+		copy(clone.Roles, t.Roles[:copyNumLinks])
+		return &clone
+	}
+
+	for linkIndex, link := range original.Roles {
+		if link.ID == "" {
+			return nil, fmt.Errorf("Detected corrupted token within the state store - missing role link ID")
+		}
+
+		role, err := s.getRoleWithTxn(tx, nil, link.ID, "id")
+
+		if err != nil {
+			return nil, err
+		}
+
+		if role == nil {
+			if !owned {
+				// clone the token as we cannot touch the original
+				token = cloneToken(original, linkIndex)
+				owned = true
+			}
+			// if already owned then we just don't append it.
+		} else if role.Name != link.Name {
+			if !owned {
+				token = cloneToken(original, linkIndex)
+				owned = true
+			}
+
+			// append the corrected role
+			token.Roles = append(token.Roles, structs.ACLTokenRoleLink{ID: link.ID, Name: role.Name})
+		} else if owned {
+			token.Roles = append(token.Roles, link)
+		}
+	}
+
+	return token, nil
+}
+
+// fixupTokenLinks corrects both the policy and role links of a token.
+func (s *Store) fixupTokenLinks(tx *memdb.Txn, original *structs.ACLToken) (*structs.ACLToken, error) {
+	token, err := s.fixupTokenPolicyLinks(tx, original)
+	if err != nil {
+		return nil, err
+	}
+	return s.fixupTokenRoleLinks(tx, token)
+}
+
 // ACLTokenSet is used to insert an ACL rule into the state store.
 func (s *Store) ACLTokenSet(idx uint64, token *structs.ACLToken, legacy bool) error {
 	tx := s.db.Txn(true)
@@ -416,6 +661,10 @@ func (s *Store) aclTokenSetTxn(tx *memdb.Txn, idx uint64, token *structs.ACLToke
 		return err
 	}
 
+	if err := s.resolveTokenRoleLinks(tx, token, allowMissingPolicyIDs); err != nil {
+		return err
+	}
+
 	// Set the indexes
 	if original != nil {
 		if original.AccessorID != "" && token.AccessorID != original.AccessorID {
@@ -495,7 +744,7 @@ func (s *Store) aclTokenGetTxn(tx *memdb.Txn, ws memdb.WatchSet, value, index st
 	ws.Add(watchCh)
 
 	if rawToken != nil {
-		token, err := s.fixupTokenPolicyLinks(tx, rawToken.(*structs.ACLToken))
+		token, err := s.fixupTokenLinks(tx, rawToken.(*structs.ACLToken))
 		if err != nil {
 			return nil, err
 		}
@@ -505,8 +754,9 @@ func (s *Store) aclTokenGetTxn(tx *memdb.Txn, ws memdb.WatchSet, value, index st
 	return nil, nil
 }
 
-// ACLTokenList is used to list out all of the ACLs in the state store.
-func (s *Store) ACLTokenList(ws memdb.WatchSet, local, global bool, policy string) (uint64, structs.ACLTokens, error) {
+// ACLTokenList is used to list out all of the ACLs in the state store. At
+// most one of the policy and role filters may be given.
+func (s *Store) ACLTokenList(ws memdb.WatchSet, local, global bool, policy, role string) (uint64, structs.ACLTokens, error) {
 	tx := s.db.Txn(false)
 	defer tx.Abort()
 
@@ -517,8 +767,16 @@ func (s *Store) ACLTokenList(ws memdb.WatchSet, local, global bool, policy strin
 	// to false but for defaulted structs (zero values for both) we want it to list out
 	// all tokens so our checks just ensure that global == local
 
-	if policy != "" {
-		iter, err = tx.Get("acl-tokens", "policies", policy)
+	if policy != "" && role != "" {
+		return 0, nil, fmt.Errorf("cannot filter by role and policy at the same time")
+	}
+
+	if policy != "" || role != "" {
+		if policy != "" {
+			iter, err = tx.Get("acl-tokens", "policies", policy)
+		} else {
+			iter, err = tx.Get("acl-tokens", "roles", role)
+		}
 		if err == nil && global != local {
 			iter = memdb.NewFilterIterator(iter, func(raw interface{}) bool {
 				token, ok := raw.(*structs.ACLToken)
@@ -550,7 +808,7 @@ func (s *Store) ACLTokenList(ws memdb.WatchSet, local, global bool, policy strin
 
 	var result structs.ACLTokens
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
-		token, err := s.fixupTokenPolicyLinks(tx, raw.(*structs.ACLToken))
+		token, err := s.fixupTokenLinks(tx, raw.(*structs.ACLToken))
 
 		if err != nil {
 			return 0, nil, err
@@ -586,6 +844,39 @@ func (s *Store) ACLTokenListUpgradeable(max int) (structs.ACLTokens, <-chan stru
 	return tokens, iter.WatchCh(), nil
 }
 
+// ACLTokenListExpired returns up to max local or global tokens that had
+// expired as of the given time, oldest expiration first.
+func (s *Store) ACLTokenListExpired(local bool, asOf time.Time, max int) (structs.ACLTokens, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	index := "expires-global"
+	if local {
+		index = "expires-local"
+	}
+
+	iter, err := tx.Get("acl-tokens", index)
+	if err != nil {
+		return nil, fmt.Errorf("failed acl token listing: %v", err)
+	}
+
+	var tokens structs.ACLTokens
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if !token.IsExpired(asOf) {
+			// the index is ordered so no later token has expired either
+			break
+		}
+
+		tokens = append(tokens, token)
+		if len(tokens) >= max {
+			break
+		}
+	}
+
+	return tokens, nil
+}
+
 // ACLTokenDeleteBySecret is used to remove an existing ACL from the state store. If
 // the ACL does not exist this is a no-op and no error is returned.
 func (s *Store) ACLTokenDeleteBySecret(idx uint64, secret string) error {
@@ -876,3 +1167,298 @@ func (s *Store) aclPolicyDeleteTxn(tx *memdb.Txn, idx uint64, value, index strin
 	}
 	return nil
 }
+
+func (s *Store) ACLRoleBatchSet(idx uint64, roles structs.ACLRoles, allowMissingPolicyIDs bool) error {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	for _, role := range roles {
+		if err := s.aclRoleSetTxn(tx, idx, role, allowMissingPolicyIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := indexUpdateMaxTxn(tx, idx, "acl-roles"); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
+func (s *Store) ACLRoleSet(idx uint64, role *structs.ACLRole) error {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	if err := s.aclRoleSetTxn(tx, idx, role, false); err != nil {
+		return err
+	}
+	if err := indexUpdateMaxTxn(tx, idx, "acl-roles"); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
+func (s *Store) aclRoleSetTxn(tx *memdb.Txn, idx uint64, role *structs.ACLRole, allowMissing bool) error {
+	// Check that the ID is set
+	if role.ID == "" {
+		return ErrMissingACLRoleID
+	}
+
+	if role.Name == "" {
+		return ErrMissingACLRoleName
+	}
+
+	existing, err := tx.First("acl-roles", "id", role.ID)
+	if err != nil {
+		return fmt.Errorf("failed acl role lookup: %v", err)
+	}
+
+	// ensure the name is unique (cannot conflict with another role with a different ID)
+	nameMatch, err := tx.First("acl-roles", "name", role.Name)
+	if err != nil {
+		return fmt.Errorf("failed acl role lookup: %v", err)
+	}
+	if nameMatch != nil && role.ID != nameMatch.(*structs.ACLRole).ID {
+		return fmt.Errorf("A role with name %q already exists", role.Name)
+	}
+
+	if err := s.resolveRolePolicyLinks(tx, role, allowMissing); err != nil {
+		return err
+	}
+
+	// Set the indexes
+	if existing != nil {
+		role.CreateIndex = existing.(*structs.ACLRole).CreateIndex
+		role.ModifyIndex = idx
+	} else {
+		role.CreateIndex = idx
+		role.ModifyIndex = idx
+	}
+
+	if err := tx.Insert("acl-roles", role); err != nil {
+		return fmt.Errorf("failed inserting acl role: %v", err)
+	}
+	return nil
+}
+
+func (s *Store) resolveRolePolicyLinks(tx *memdb.Txn, role *structs.ACLRole, allowMissing bool) error {
+	for linkIndex, link := range role.Policies {
+		if link.ID != "" {
+			policy, err := s.getPolicyWithTxn(tx, nil, link.ID, "id")
+
+			if err != nil {
+				return err
+			}
+
+			if policy != nil {
+				// the name doesn't matter here
+				role.Policies[linkIndex].Name = policy.Name
+			} else if !allowMissing {
+				return fmt.Errorf("No such policy with ID: %s", link.ID)
+			}
+		} else {
+			return fmt.Errorf("Encountered a Role with policies linked by Name in the state store")
+		}
+	}
+	return nil
+}
+
+// fixupRolePolicyLinks is to be used when retrieving roles from memdb. The
+// policy links could have gotten stale when a linked policy was deleted or
+// renamed. This will correct them and generate a newly allocated role only
+// when fixes are needed.
+func (s *Store) fixupRolePolicyLinks(tx *memdb.Txn, original *structs.ACLRole) (*structs.ACLRole, error) {
+	owned := false
+	role := original
+
+	cloneRole := func(r *structs.ACLRole, copyNumLinks int) *structs.ACLRole {
+		clone := *r
+		clone.Policies = make([]structs.ACLRolePolicyLink, copyNumLinks)
+		copy(clone.Policies, r.Policies[:copyNumLinks])
+		return &clone
+	}
+
+	for linkIndex, link := range original.Policies {
+		if link.ID == "" {
+			return nil, fmt.Errorf("Detected corrupted role within the state store - missing policy link ID")
+		}
+
+		policy, err := s.getPolicyWithTxn(tx, nil, link.ID, "id")
+
+		if err != nil {
+			return nil, err
+		}
+
+		if policy == nil {
+			if !owned {
+				// clone the role as we cannot touch the original
+				role = cloneRole(original, linkIndex)
+				owned = true
+			}
+			// if already owned then we just don't append it.
+		} else if policy.Name != link.Name {
+			if !owned {
+				role = cloneRole(original, linkIndex)
+				owned = true
+			}
+
+			// append the corrected policy
+			role.Policies = append(role.Policies, structs.ACLRolePolicyLink{ID: link.ID, Name: policy.Name})
+		} else if owned {
+			role.Policies = append(role.Policies, link)
+		}
+	}
+
+	return role, nil
+}
+
+func (s *Store) ACLRoleGetByID(ws memdb.WatchSet, id string) (uint64, *structs.ACLRole, error) {
+	return s.aclRoleGet(ws, id, "id")
+}
+
+func (s *Store) ACLRoleGetByName(ws memdb.WatchSet, name string) (uint64, *structs.ACLRole, error) {
+	return s.aclRoleGet(ws, name, "name")
+}
+
+func (s *Store) ACLRoleBatchGet(ws memdb.WatchSet, ids []string) (uint64, structs.ACLRoles, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	roles := make(structs.ACLRoles, 0)
+	for _, rid := range ids {
+		role, err := s.getRoleWithTxn(tx, ws, rid, "id")
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if role != nil {
+			roles = append(roles, role)
+		}
+	}
+
+	idx := maxIndexTxn(tx, "acl-roles")
+
+	return idx, roles, nil
+}
+
+func (s *Store) getRoleWithTxn(tx *memdb.Txn, ws memdb.WatchSet, value, index string) (*structs.ACLRole, error) {
+	watchCh, rawRole, err := tx.FirstWatch("acl-roles", index, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed acl role lookup: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if rawRole == nil {
+		return nil, nil
+	}
+
+	return s.fixupRolePolicyLinks(tx, rawRole.(*structs.ACLRole))
+}
+
+func (s *Store) aclRoleGet(ws memdb.WatchSet, value, index string) (uint64, *structs.ACLRole, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	role, err := s.getRoleWithTxn(tx, ws, value, index)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	idx := maxIndexTxn(tx, "acl-roles")
+
+	return idx, role, nil
+}
+
+// ACLRoleList lists all roles, or only those linking the given policy.
+func (s *Store) ACLRoleList(ws memdb.WatchSet, policy string) (uint64, structs.ACLRoles, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	if policy != "" {
+		iter, err = tx.Get("acl-roles", "policies", policy)
+	} else {
+		iter, err = tx.Get("acl-roles", "id")
+	}
+
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed acl role lookup: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var result structs.ACLRoles
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		role, err := s.fixupRolePolicyLinks(tx, raw.(*structs.ACLRole))
+		if err != nil {
+			return 0, nil, err
+		}
+		result = append(result, role)
+	}
+
+	// Get the table index.
+	idx := maxIndexTxn(tx, "acl-roles")
+
+	return idx, result, nil
+}
+
+func (s *Store) ACLRoleDeleteByID(idx uint64, id string) error {
+	return s.aclRoleDelete(idx, id, "id")
+}
+
+func (s *Store) ACLRoleDeleteByName(idx uint64, name string) error {
+	return s.aclRoleDelete(idx, name, "name")
+}
+
+func (s *Store) ACLRoleBatchDelete(idx uint64, roleIDs []string) error {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	for _, roleID := range roleIDs {
+		if err := s.aclRoleDeleteTxn(tx, idx, roleID, "id"); err != nil {
+			return err
+		}
+	}
+
+	if err := indexUpdateMaxTxn(tx, idx, "acl-roles"); err != nil {
+		return fmt.Errorf("failed updating index: %v", err)
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *Store) aclRoleDelete(idx uint64, value, index string) error {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	if err := s.aclRoleDeleteTxn(tx, idx, value, index); err != nil {
+		return err
+	}
+	if err := indexUpdateMaxTxn(tx, idx, "acl-roles"); err != nil {
+		return fmt.Errorf("failed updating index: %v", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
+func (s *Store) aclRoleDeleteTxn(tx *memdb.Txn, idx uint64, value, index string) error {
+	// Look up the existing role
+	rawRole, err := tx.First("acl-roles", index, value)
+	if err != nil {
+		return fmt.Errorf("failed acl role lookup: %v", err)
+	}
+
+	if rawRole == nil {
+		return nil
+	}
+
+	if err := tx.Delete("acl-roles", rawRole); err != nil {
+		return fmt.Errorf("failed deleting acl role: %v", err)
+	}
+	return nil
+}
@@ -140,7 +140,7 @@ func TestStateStore_ACLBootstrap(t *testing.T) {
 	require.Equal(t, uint64(3), index)
 
 	// Make sure the ACLs are in an expected state.
-	_, tokens, err := s.ACLTokenList(nil, true, true, "")
+	_, tokens, err := s.ACLTokenList(nil, true, true, "", "")
 	require.NoError(t, err)
 	require.Len(t, tokens, 1)
 	compareTokens(token1, tokens[0])
@@ -154,7 +154,7 @@ func TestStateStore_ACLBootstrap(t *testing.T) {
 	err = s.ACLBootstrap(32, index, token2.Clone(), false)
 	require.NoError(t, err)
 
-	_, tokens, err = s.ACLTokenList(nil, true, true, "")
+	_, tokens, err = s.ACLTokenList(nil, true, true, "", "")
 	require.NoError(t, err)
 	require.Len(t, tokens, 2)
 }
@@ -662,6 +662,66 @@ func TestStateStore_ACLTokens_ListUpgradeable(t *testing.T) {
 	require.Len(t, tokens, 0)
 }
 
+func TestStateStore_ACLToken_ListExpired(t *testing.T) {
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	now := time.Now()
+	expiresAt := func(d time.Duration) *time.Time {
+		exp := now.Add(d)
+		return &exp
+	}
+
+	tokens := structs.ACLTokens{
+		&structs.ACLToken{
+			AccessorID:     "a1b9d8ad-7b4d-4a2b-91de-3e0c9c4e77f1",
+			SecretID:       "2a1e2bd0-4b65-4b3c-9f58-b2f1d44a9a01",
+			ExpirationTime: expiresAt(-2 * time.Hour),
+		},
+		&structs.ACLToken{
+			AccessorID:     "b7a0f3c2-5f64-4e57-8f7d-3a9c8e0d1b22",
+			SecretID:       "4c7dfd2f-7bd1-4ad4-8d41-7f9a0b3c5d02",
+			ExpirationTime: expiresAt(-1 * time.Hour),
+		},
+		&structs.ACLToken{
+			AccessorID:     "c3e5a7b9-1d2f-4a6c-8e0b-5d7f9a1c3e33",
+			SecretID:       "6e9f1a3b-5c7d-4e2f-9a0b-1c3d5e7f9a03",
+			ExpirationTime: expiresAt(-1 * time.Hour),
+			Local:          true,
+		},
+		&structs.ACLToken{
+			AccessorID:     "d9f1b3c5-7e0a-4b2d-9c4e-6f8a0b2c4d44",
+			SecretID:       "8a0b2c4d-6e8f-4a1b-8c3d-5e7f9a1b3c04",
+			ExpirationTime: expiresAt(time.Hour),
+		},
+		&structs.ACLToken{
+			AccessorID: "e5a7c9e1-3b5d-4f7a-8b0c-2d4e6f8a0b55",
+			SecretID:   "0c2e4a6b-8d0f-4b3c-9d5e-7f9a1b3c5d05",
+		},
+	}
+	require.NoError(t, s.ACLTokenBatchSet(2, tokens, false))
+
+	expired, err := s.ACLTokenListExpired(false, now, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 2)
+	require.Equal(t, "a1b9d8ad-7b4d-4a2b-91de-3e0c9c4e77f1", expired[0].AccessorID)
+	require.Equal(t, "b7a0f3c2-5f64-4e57-8f7d-3a9c8e0d1b22", expired[1].AccessorID)
+
+	expired, err = s.ACLTokenListExpired(false, now, 1)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "a1b9d8ad-7b4d-4a2b-91de-3e0c9c4e77f1", expired[0].AccessorID)
+
+	expired, err = s.ACLTokenListExpired(true, now, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "c3e5a7b9-1d2f-4a6c-8e0b-5d7f9a1c3e33", expired[0].AccessorID)
+
+	expired, err = s.ACLTokenListExpired(false, now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 3)
+}
+
 func TestStateStore_ACLToken_List(t *testing.T) {
 	t.Parallel()
 	s := testACLTokensStateStore(t)
@@ -790,7 +850,7 @@ func TestStateStore_ACLToken_List(t *testing.T) {
 		tc := tc // capture range variable
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			_, tokens, err := s.ACLTokenList(nil, tc.local, tc.global, tc.policy)
+			_, tokens, err := s.ACLTokenList(nil, tc.local, tc.global, tc.policy, "")
 			require.NoError(t, err)
 			require.Len(t, tokens, len(tc.accessors))
 			tokens.Sort()
@@ -851,7 +911,7 @@ func TestStateStore_ACLToken_FixupPolicyLinks(t *testing.T) {
 	require.Equal(t, "node-read-renamed", retrieved.Policies[0].Name)
 
 	// list tokens without stale links
-	_, tokens, err := s.ACLTokenList(nil, true, true, "")
+	_, tokens, err := s.ACLTokenList(nil, true, true, "", "")
 	require.NoError(t, err)
 
 	found := false
@@ -895,7 +955,7 @@ func TestStateStore_ACLToken_FixupPolicyLinks(t *testing.T) {
 	require.Len(t, retrieved.Policies, 0)
 
 	// list tokens without stale links
-	_, tokens, err = s.ACLTokenList(nil, true, true, "")
+	_, tokens, err = s.ACLTokenList(nil, true, true, "", "")
 	require.NoError(t, err)
 
 	found = false
@@ -1564,7 +1624,7 @@ func TestStateStore_ACLTokens_Snapshot_Restore(t *testing.T) {
 		require.NoError(t, s.ACLPolicyBatchSet(2, policies))
 
 		// Read the restored ACLs back out and verify that they match.
-		idx, res, err := s.ACLTokenList(nil, true, true, "")
+		idx, res, err := s.ACLTokenList(nil, true, true, "", "")
 		require.NoError(t, err)
 		require.Equal(t, uint64(2), idx)
 		require.ElementsMatch(t, tokens, res)
@@ -1632,3 +1692,410 @@ func TestStateStore_ACLPolicies_Snapshot_Restore(t *testing.T) {
 		require.Equal(t, uint64(2), s.maxIndex("acl-policies"))
 	}()
 }
+
+func TestStateStore_ACLRole_SetGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Missing ID", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		role := structs.ACLRole{
+			Name: "test-role",
+		}
+
+		require.Error(t, s.ACLRoleSet(3, &role))
+	})
+
+	t.Run("Missing Name", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		role := structs.ACLRole{
+			ID: "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+		}
+
+		require.Error(t, s.ACLRoleSet(3, &role))
+	})
+
+	t.Run("Unresolvable Policy ID", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		role := structs.ACLRole{
+			ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name: "test-role",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: "4f20e379-b496-4b99-9599-19a197126490",
+				},
+			},
+		}
+
+		require.Error(t, s.ACLRoleSet(3, &role))
+	})
+
+	t.Run("Name Conflict", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		require.NoError(t, s.ACLRoleSet(3, &structs.ACLRole{
+			ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name: "test-role",
+		}))
+
+		require.Error(t, s.ACLRoleSet(4, &structs.ACLRole{
+			ID:   "b0e37bbc-8b10-4ea3-bd93-43f2a86ab0c3",
+			Name: "Test-Role",
+		}))
+	})
+
+	t.Run("New", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		role := structs.ACLRole{
+			ID:          "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name:        "test-role",
+			Description: "test",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: "a0625e95-9b3e-42de-a8d6-ceef5b6f3286",
+				},
+			},
+		}
+
+		require.NoError(t, s.ACLRoleSet(3, &role))
+
+		idx, rrole, err := s.ACLRoleGetByID(nil, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), idx)
+		require.NotNil(t, rrole)
+		require.Equal(t, "test-role", rrole.Name)
+		require.Equal(t, "test", rrole.Description)
+		require.Len(t, rrole.Policies, 1)
+		require.Equal(t, "node-read", rrole.Policies[0].Name)
+		require.Equal(t, uint64(3), rrole.CreateIndex)
+		require.Equal(t, uint64(3), rrole.ModifyIndex)
+
+		// role names are case insensitive
+		_, rrole, err = s.ACLRoleGetByName(nil, "Test-Role")
+		require.NoError(t, err)
+		require.NotNil(t, rrole)
+		require.Equal(t, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47", rrole.ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		role := structs.ACLRole{
+			ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name: "test-role",
+		}
+		require.NoError(t, s.ACLRoleSet(3, &role))
+
+		updated := structs.ACLRole{
+			ID:          "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name:        "test-role-modified",
+			Description: "modified",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: structs.ACLPolicyGlobalManagementID,
+				},
+			},
+		}
+		require.NoError(t, s.ACLRoleSet(4, &updated))
+
+		idx, rrole, err := s.ACLRoleGetByID(nil, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47")
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), idx)
+		require.Equal(t, "test-role-modified", rrole.Name)
+		require.Equal(t, "modified", rrole.Description)
+		require.Len(t, rrole.Policies, 1)
+		require.Equal(t, "global-management", rrole.Policies[0].Name)
+		require.Equal(t, uint64(3), rrole.CreateIndex)
+		require.Equal(t, uint64(4), rrole.ModifyIndex)
+
+		_, rrole, err = s.ACLRoleGetByName(nil, "test-role")
+		require.NoError(t, err)
+		require.Nil(t, rrole)
+	})
+}
+
+func TestStateStore_ACLRole_List(t *testing.T) {
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	roles := structs.ACLRoles{
+		&structs.ACLRole{
+			ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name: "node-reader",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: "a0625e95-9b3e-42de-a8d6-ceef5b6f3286",
+				},
+			},
+		},
+		&structs.ACLRole{
+			ID:   "b0e37bbc-8b10-4ea3-bd93-43f2a86ab0c3",
+			Name: "operator",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID: structs.ACLPolicyGlobalManagementID,
+				},
+			},
+		},
+	}
+	require.NoError(t, s.ACLRoleBatchSet(3, roles, false))
+
+	idx, rroles, err := s.ACLRoleList(nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), idx)
+	require.Len(t, rroles, 2)
+	rroles.Sort()
+	require.Equal(t, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47", rroles[0].ID)
+	require.Equal(t, "b0e37bbc-8b10-4ea3-bd93-43f2a86ab0c3", rroles[1].ID)
+
+	_, rroles, err = s.ACLRoleList(nil, structs.ACLPolicyGlobalManagementID)
+	require.NoError(t, err)
+	require.Len(t, rroles, 1)
+	require.Equal(t, "operator", rroles[0].Name)
+}
+
+func TestStateStore_ACLRole_FixupPolicyLinks(t *testing.T) {
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	role := structs.ACLRole{
+		ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+		Name: "test-role",
+		Policies: []structs.ACLRolePolicyLink{
+			structs.ACLRolePolicyLink{
+				ID: "a0625e95-9b3e-42de-a8d6-ceef5b6f3286",
+			},
+		},
+	}
+	require.NoError(t, s.ACLRoleSet(3, &role))
+
+	// rename the policy
+	renamed := &structs.ACLPolicy{
+		ID:    "a0625e95-9b3e-42de-a8d6-ceef5b6f3286",
+		Name:  "node-read-renamed",
+		Rules: `node_prefix "" { policy = "read" }`,
+	}
+	require.NoError(t, s.ACLPolicySet(4, renamed))
+
+	_, rrole, err := s.ACLRoleGetByID(nil, role.ID)
+	require.NoError(t, err)
+	require.Len(t, rrole.Policies, 1)
+	require.Equal(t, "node-read-renamed", rrole.Policies[0].Name)
+
+	// then delete it
+	require.NoError(t, s.ACLPolicyDeleteByID(5, renamed.ID))
+
+	_, rrole, err = s.ACLRoleGetByID(nil, role.ID)
+	require.NoError(t, err)
+	require.Len(t, rrole.Policies, 0)
+}
+
+func TestStateStore_ACLRole_Delete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ID", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		require.NoError(t, s.ACLRoleSet(3, &structs.ACLRole{
+			ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name: "test-role",
+		}))
+
+		require.NoError(t, s.ACLRoleDeleteByID(4, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47"))
+
+		_, rrole, err := s.ACLRoleGetByID(nil, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47")
+		require.NoError(t, err)
+		require.Nil(t, rrole)
+	})
+
+	t.Run("Name", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		require.NoError(t, s.ACLRoleSet(3, &structs.ACLRole{
+			ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name: "test-role",
+		}))
+
+		require.NoError(t, s.ACLRoleDeleteByName(4, "test-role"))
+
+		_, rrole, err := s.ACLRoleGetByName(nil, "test-role")
+		require.NoError(t, err)
+		require.Nil(t, rrole)
+	})
+
+	t.Run("Multiple", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		roles := structs.ACLRoles{
+			&structs.ACLRole{
+				ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+				Name: "role1",
+			},
+			&structs.ACLRole{
+				ID:   "b0e37bbc-8b10-4ea3-bd93-43f2a86ab0c3",
+				Name: "role2",
+			},
+		}
+		require.NoError(t, s.ACLRoleBatchSet(3, roles, false))
+
+		require.NoError(t, s.ACLRoleBatchDelete(4, []string{
+			"2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			"b0e37bbc-8b10-4ea3-bd93-43f2a86ab0c3"}))
+
+		_, rroles, err := s.ACLRoleList(nil, "")
+		require.NoError(t, err)
+		require.Len(t, rroles, 0)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+
+		// deletion of non-existent roles is not an error
+		require.NoError(t, s.ACLRoleDeleteByName(3, "not-found"))
+		require.NoError(t, s.ACLRoleDeleteByID(3, "376d0cae-dd50-4213-9668-2c7797a7fb2d"))
+	})
+}
+
+func TestStateStore_ACLToken_Roles(t *testing.T) {
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	require.NoError(t, s.ACLRoleSet(3, &structs.ACLRole{
+		ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+		Name: "test-role",
+	}))
+
+	t.Run("Unresolvable Role ID", func(t *testing.T) {
+		token := &structs.ACLToken{
+			AccessorID: "daf37c07-d04d-4fd5-9678-a8206a57d61a",
+			SecretID:   "39171632-6f34-4411-827f-9416403687f4",
+			Roles: []structs.ACLTokenRoleLink{
+				structs.ACLTokenRoleLink{
+					ID: "4f20e379-b496-4b99-9599-19a197126490",
+				},
+			},
+		}
+		require.Error(t, s.ACLTokenSet(4, token, false))
+	})
+
+	token := &structs.ACLToken{
+		AccessorID: "daf37c07-d04d-4fd5-9678-a8206a57d61a",
+		SecretID:   "39171632-6f34-4411-827f-9416403687f4",
+		Roles: []structs.ACLTokenRoleLink{
+			structs.ACLTokenRoleLink{
+				ID: "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			},
+		},
+	}
+	require.NoError(t, s.ACLTokenSet(4, token, false))
+
+	_, rtoken, err := s.ACLTokenGetByAccessor(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.Len(t, rtoken.Roles, 1)
+	require.Equal(t, "test-role", rtoken.Roles[0].Name)
+
+	// filter the listing by role
+	_, tokens, err := s.ACLTokenList(nil, true, true, "", "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, token.AccessorID, tokens[0].AccessorID)
+
+	// filtering by policy and role at once is not supported
+	_, _, err = s.ACLTokenList(nil, true, true, "a0625e95-9b3e-42de-a8d6-ceef5b6f3286", "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47")
+	require.Error(t, err)
+
+	// renaming the role is reflected in the link
+	require.NoError(t, s.ACLRoleSet(5, &structs.ACLRole{
+		ID:   "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+		Name: "test-role-renamed",
+	}))
+	_, rtoken, err = s.ACLTokenGetByAccessor(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.Len(t, rtoken.Roles, 1)
+	require.Equal(t, "test-role-renamed", rtoken.Roles[0].Name)
+
+	// deleting the role drops the link
+	require.NoError(t, s.ACLRoleDeleteByID(6, "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47"))
+	_, rtoken, err = s.ACLTokenGetByAccessor(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.Len(t, rtoken.Roles, 0)
+}
+
+func TestStateStore_ACLRoles_Snapshot_Restore(t *testing.T) {
+	s := testACLTokensStateStore(t)
+
+	roles := structs.ACLRoles{
+		&structs.ACLRole{
+			ID:          "2c74a9b8-271c-4a23-b6c5-8a6b3e3e0e47",
+			Name:        "role1",
+			Description: "role1",
+			Policies: []structs.ACLRolePolicyLink{
+				structs.ACLRolePolicyLink{
+					ID:   "a0625e95-9b3e-42de-a8d6-ceef5b6f3286",
+					Name: "node-read",
+				},
+			},
+			Hash:      []byte{1, 2, 3, 4},
+			RaftIndex: structs.RaftIndex{CreateIndex: 1, ModifyIndex: 2},
+		},
+		&structs.ACLRole{
+			ID:          "b0e37bbc-8b10-4ea3-bd93-43f2a86ab0c3",
+			Name:        "role2",
+			Description: "role2",
+			Hash:        []byte{1, 2, 3, 4},
+			RaftIndex:   structs.RaftIndex{CreateIndex: 1, ModifyIndex: 2},
+		},
+	}
+
+	require.NoError(t, s.ACLRoleBatchSet(3, roles, false))
+
+	// Snapshot the ACLs.
+	snap := s.Snapshot()
+	defer snap.Close()
+
+	// Alter the real state store.
+	require.NoError(t, s.ACLRoleDeleteByID(4, roles[0].ID))
+
+	// Verify the snapshot.
+	require.Equal(t, uint64(3), snap.LastIndex())
+
+	iter, err := snap.ACLRoles()
+	require.NoError(t, err)
+
+	var dump structs.ACLRoles
+	for role := iter.Next(); role != nil; role = iter.Next() {
+		dump = append(dump, role.(*structs.ACLRole))
+	}
+	require.ElementsMatch(t, dump, roles)
+
+	// Restore the values into a new state store.
+	func() {
+		// the linked policy has to exist for the link to survive a read
+		s := testACLTokensStateStore(t)
+		restore := s.Restore()
+		for _, role := range dump {
+			require.NoError(t, restore.ACLRole(role))
+		}
+		restore.Commit()
+
+		// Read the restored ACLs back out and verify that they match.
+		idx, res, err := s.ACLRoleList(nil, "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), idx)
+		require.ElementsMatch(t, roles, res)
+		require.Equal(t, uint64(3), s.maxIndex("acl-roles"))
+	}()
+}
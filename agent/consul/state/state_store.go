@@ -37,6 +37,14 @@ var (
 	// policy with an empty Name.
 	ErrMissingACLPolicyName = errors.New("Missing ACL Policy Name")
 
+	// ErrMissingACLRoleID is returned when a role set is called on
+	// a role with an empty ID.
+	ErrMissingACLRoleID = errors.New("Missing ACL Role ID")
+
+	// ErrMissingACLRoleName is returned when a role set is called on
+	// a role with an empty Name.
+	ErrMissingACLRoleName = errors.New("Missing ACL Role Name")
+
 	// ErrMissingQueryID is returned when a Query set is called on
 	// a Query with an empty ID.
 	ErrMissingQueryID = errors.New("Missing Query ID")
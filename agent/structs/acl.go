@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
 	"sort"
 	"strings"
 	"time"
@@ -39,6 +40,13 @@ const (
 	ACLTokenAccessor ACLTokenIDType = "accessor"
 )
 
+type ACLRoleIDType string
+
+const (
+	ACLRoleName ACLRoleIDType = "name"
+	ACLRoleID   ACLRoleIDType = "id"
+)
+
 type ACLPolicyIDType string
 
 const (
@@ -86,8 +94,11 @@ session_prefix "" {
 	ACLTokenAnonymousID = "00000000-0000-0000-0000-000000000002"
 )
 
+// ACLReservedPrefix is the prefix of the IDs reserved for builtin ACL objects
+const ACLReservedPrefix = "00000000-0000-0000-0000-0000000000"
+
 func ACLIDReserved(id string) bool {
-	return strings.HasPrefix(id, "00000000-0000-0000-0000-0000000000")
+	return strings.HasPrefix(id, ACLReservedPrefix)
 }
 
 const (
@@ -112,7 +123,10 @@ type ACLIdentity interface {
 	ID() string
 	SecretToken() string
 	PolicyIDs() []string
+	RoleIDs() []string
+	ServiceIdentityList() []*ACLServiceIdentity
 	EmbeddedPolicy() *ACLPolicy
+	IsExpired(asOf time.Time) bool
 }
 
 type ACLTokenPolicyLink struct {
@@ -120,6 +134,101 @@ type ACLTokenPolicyLink struct {
 	Name string `hash:"ignore"`
 }
 
+type ACLTokenRoleLink struct {
+	ID   string
+	Name string `hash:"ignore"`
+}
+
+// aclPolicyTemplateServiceIdentity is the rule set synthesized for a service
+// identity. Write access to a service also grants read access to the
+// intentions where it is the destination.
+const aclPolicyTemplateServiceIdentity = `
+service "%[1]s" {
+	policy = "write"
+}
+service "%[1]s-sidecar-proxy" {
+	policy = "write"
+}
+service_prefix "" {
+	policy = "read"
+}
+node_prefix "" {
+	policy = "read"
+}
+`
+
+// ACLServiceIdentity represents a high-level grant of all necessary privileges
+// to assume the identity of the named service in the Catalog and within
+// Connect.
+type ACLServiceIdentity struct {
+	ServiceName string
+
+	// Datacenters that the synthetic policy is valid within.
+	//   - No wildcards allowed
+	//   - If empty then the policy is valid within all datacenters
+	Datacenters []string `json:",omitempty"`
+}
+
+func (s *ACLServiceIdentity) Clone() *ACLServiceIdentity {
+	s2 := *s
+	s2.Datacenters = nil
+	if len(s.Datacenters) > 0 {
+		s2.Datacenters = make([]string, len(s.Datacenters))
+		copy(s2.Datacenters, s.Datacenters)
+	}
+	return &s2
+}
+
+func (s *ACLServiceIdentity) AddToHash(h io.Writer) {
+	h.Write([]byte("service:" + s.ServiceName))
+	for _, dc := range s.Datacenters {
+		h.Write([]byte(dc))
+	}
+}
+
+func (s *ACLServiceIdentity) EstimateSize() int {
+	size := len(s.ServiceName)
+	for _, dc := range s.Datacenters {
+		size += len(dc)
+	}
+	return size
+}
+
+// SyntheticPolicy returns the policy granting the privileges of the service
+// identity. Its ID is derived from the rules and the datacenters so that equal
+// identities share the same policy, and with it the same cached authorizer.
+func (s *ACLServiceIdentity) SyntheticPolicy() *ACLPolicy {
+	rules := fmt.Sprintf(aclPolicyTemplateServiceIdentity, s.ServiceName)
+
+	hasher := fnv.New128a()
+	hasher.Write([]byte(rules))
+	for _, dc := range s.Datacenters {
+		// The separator keeps ["dc1", "2"] apart from ["dc12"].
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(dc))
+	}
+	policy := &ACLPolicy{}
+	policy.ID = fmt.Sprintf("%x", hasher.Sum(nil))
+	policy.Name = fmt.Sprintf("synthetic-policy-%s", policy.ID)
+	policy.Description = "synthetic policy"
+	policy.Rules = rules
+	policy.Syntax = acl.SyntaxCurrent
+	policy.Datacenters = s.Datacenters
+	policy.SetHash(true)
+	return policy
+}
+
+func cloneServiceIdentities(identities []*ACLServiceIdentity) []*ACLServiceIdentity {
+	if len(identities) == 0 {
+		return nil
+	}
+	out := make([]*ACLServiceIdentity, 0, len(identities))
+	for _, s := range identities {
+		out = append(out, s.Clone())
+	}
+	return out
+}
+
 type ACLToken struct {
 	// This is the UUID used for tracking and management purposes
 	AccessorID string
@@ -135,6 +244,14 @@ type ACLToken struct {
 	// the list of policy names gets validated and the policy IDs get stored herein
 	Policies []ACLTokenPolicyLink
 
+	// List of role links. Like policies these are stored by ID and the
+	// names are only filled in when the token is read.
+	Roles []ACLTokenRoleLink `json:",omitempty"`
+
+	// List of service identities whose synthetic policies are granted to
+	// the token.
+	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
+
 	// Type is the V1 Token Type
 	// DEPRECATED (ACL-Legacy-Compat) - remove once we no longer support v1 ACL compat
 	// Even though we are going to auto upgrade management tokens we still
@@ -153,6 +270,18 @@ type ACLToken struct {
 	// The time when this token was created
 	CreateTime time.Time `json:",omitempty"`
 
+	// ExpirationTime, if set, is the time after which the token can no longer
+	// be used. It is computed from ExpirationTTL when the token is created and
+	// cannot be changed afterwards.
+	ExpirationTime *time.Time `json:",omitempty"`
+
+	// ExpirationTTL is a convenience for setting ExpirationTime relative to
+	// the CreateTime of the token. It is only used when creating a token.
+	ExpirationTTL time.Duration `json:",omitempty"`
+
+	// Labels are arbitrary key/value pairs used to group and select tokens
+	Labels map[string]string `json:",omitempty"`
+
 	// Hash of the contents of the token
 	//
 	// This is needed mainly for replication purposes. When replicating from
@@ -172,6 +301,13 @@ func (t *ACLToken) Clone() *ACLToken {
 		t2.Policies = make([]ACLTokenPolicyLink, len(t.Policies))
 		copy(t2.Policies, t.Policies)
 	}
+	t2.Roles = nil
+	if len(t.Roles) > 0 {
+		t2.Roles = make([]ACLTokenRoleLink, len(t.Roles))
+		copy(t2.Roles, t.Roles)
+	}
+	t2.ServiceIdentities = cloneServiceIdentities(t.ServiceIdentities)
+	t2.Labels = cloneLabels(t.Labels)
 	return &t2
 }
 
@@ -191,6 +327,31 @@ func (t *ACLToken) PolicyIDs() []string {
 	return ids
 }
 
+func (t *ACLToken) RoleIDs() []string {
+	var ids []string
+	for _, link := range t.Roles {
+		ids = append(ids, link.ID)
+	}
+	return ids
+}
+
+func (t *ACLToken) ServiceIdentityList() []*ACLServiceIdentity {
+	return cloneServiceIdentities(t.ServiceIdentities)
+}
+
+// HasExpirationTime returns whether the token has an expiration time set.
+func (t *ACLToken) HasExpirationTime() bool {
+	return t.ExpirationTime != nil && !t.ExpirationTime.IsZero()
+}
+
+// IsExpired returns whether the token had expired as of the given time.
+func (t *ACLToken) IsExpired(asOf time.Time) bool {
+	if asOf.IsZero() || !t.HasExpirationTime() {
+		return false
+	}
+	return t.ExpirationTime.Before(asOf)
+}
+
 func (t *ACLToken) EmbeddedPolicy() *ACLPolicy {
 	// DEPRECATED (ACL-Legacy-Compat)
 	//
@@ -244,6 +405,16 @@ func (t *ACLToken) SetHash(force bool) []byte {
 			hash.Write([]byte(link.ID))
 		}
 
+		for _, link := range t.Roles {
+			hash.Write([]byte("role:" + link.ID))
+		}
+
+		for _, s := range t.ServiceIdentities {
+			s.AddToHash(hash)
+		}
+
+		hashLabels(hash, t.Labels)
+
 		// Finalize the hash
 		hashVal := hash.Sum(nil)
 
@@ -254,11 +425,20 @@ func (t *ACLToken) SetHash(force bool) []byte {
 }
 
 func (t *ACLToken) EstimateSize() int {
-	// 33 = 16 (RaftIndex) + 8 (Hash) + 8 (CreateTime) + 1 (Local)
-	size := 33 + len(t.AccessorID) + len(t.SecretID) + len(t.Description) + len(t.Type) + len(t.Rules)
+	// 41 = 16 (RaftIndex) + 8 (Hash) + 8 (CreateTime) + 8 (ExpirationTime) + 1 (Local)
+	size := 41 + len(t.AccessorID) + len(t.SecretID) + len(t.Description) + len(t.Type) + len(t.Rules)
 	for _, link := range t.Policies {
 		size += len(link.ID) + len(link.Name)
 	}
+	for _, link := range t.Roles {
+		size += len(link.ID) + len(link.Name)
+	}
+	for _, s := range t.ServiceIdentities {
+		size += s.EstimateSize()
+	}
+	for k, v := range t.Labels {
+		size += len(k) + len(v)
+	}
 	return size
 }
 
@@ -266,30 +446,38 @@ func (t *ACLToken) EstimateSize() int {
 type ACLTokens []*ACLToken
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Description string
-	Policies    []ACLTokenPolicyLink
-	Local       bool
-	CreateTime  time.Time `json:",omitempty"`
-	Hash        []byte
-	CreateIndex uint64
-	ModifyIndex uint64
-	Legacy      bool `json:",omitempty"`
+	AccessorID        string
+	Description       string
+	Policies          []ACLTokenPolicyLink
+	Roles             []ACLTokenRoleLink    `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
+	Local             bool
+	CreateTime        time.Time         `json:",omitempty"`
+	ExpirationTime    *time.Time        `json:",omitempty"`
+	Labels            map[string]string `json:",omitempty"`
+	Hash              []byte
+	CreateIndex       uint64
+	ModifyIndex       uint64
+	Legacy            bool `json:",omitempty"`
 }
 
 type ACLTokenListStubs []*ACLTokenListStub
 
 func (token *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  token.AccessorID,
-		Description: token.Description,
-		Policies:    token.Policies,
-		Local:       token.Local,
-		CreateTime:  token.CreateTime,
-		Hash:        token.Hash,
-		CreateIndex: token.CreateIndex,
-		ModifyIndex: token.ModifyIndex,
-		Legacy:      token.Rules != "",
+		AccessorID:        token.AccessorID,
+		Description:       token.Description,
+		Policies:          token.Policies,
+		Roles:             token.Roles,
+		ServiceIdentities: token.ServiceIdentities,
+		Local:             token.Local,
+		CreateTime:        token.CreateTime,
+		ExpirationTime:    token.ExpirationTime,
+		Labels:            token.Labels,
+		Hash:              token.Hash,
+		CreateIndex:       token.CreateIndex,
+		ModifyIndex:       token.ModifyIndex,
+		Legacy:            token.Rules != "",
 	}
 }
 
@@ -328,6 +516,9 @@ type ACLPolicy struct {
 	//   - If empty then the policy is valid within all datacenters
 	Datacenters []string `json:",omitempty"`
 
+	// Labels are arbitrary key/value pairs used to group and select policies
+	Labels map[string]string `json:",omitempty"`
+
 	// Hash of the contents of the policy
 	// This does not take into account the ID (which is immutable)
 	// nor the raft metadata.
@@ -348,6 +539,7 @@ func (p *ACLPolicy) Clone() *ACLPolicy {
 		p2.Datacenters = make([]string, len(p.Datacenters))
 		copy(p2.Datacenters, p.Datacenters)
 	}
+	p2.Labels = cloneLabels(p.Labels)
 	return &p2
 }
 
@@ -356,6 +548,7 @@ type ACLPolicyListStub struct {
 	Name        string
 	Description string
 	Datacenters []string
+	Labels      map[string]string `json:",omitempty"`
 	Hash        []byte
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -367,6 +560,7 @@ func (p *ACLPolicy) Stub() *ACLPolicyListStub {
 		Name:        p.Name,
 		Description: p.Description,
 		Datacenters: p.Datacenters,
+		Labels:      p.Labels,
 		Hash:        p.Hash,
 		CreateIndex: p.CreateIndex,
 		ModifyIndex: p.ModifyIndex,
@@ -392,6 +586,8 @@ func (p *ACLPolicy) SetHash(force bool) []byte {
 			hash.Write([]byte(dc))
 		}
 
+		hashLabels(hash, p.Labels)
+
 		// Finalize the hash
 		hashVal := hash.Sum(nil)
 
@@ -410,6 +606,9 @@ func (p *ACLPolicy) EstimateSize() int {
 	for _, dc := range p.Datacenters {
 		size += len(dc)
 	}
+	for k, v := range p.Labels {
+		size += len(k) + len(v)
+	}
 
 	return size
 }
@@ -500,11 +699,187 @@ func (policies ACLPolicies) Merge(cache *ACLCaches, sentinel sentinel.Evaluator)
 	return acl.MergePolicies(parsed), nil
 }
 
+type ACLRolePolicyLink = ACLTokenPolicyLink
+
+// ACLRole is a named set of policies that can be linked to tokens instead
+// of linking each of the policies individually.
+type ACLRole struct {
+	// ID is the internal UUID associated with the role
+	ID string
+
+	// Name is the unique name to reference the role by.
+	Name string
+
+	// Description is a human readable description (Optional)
+	Description string
+
+	// List of policy links.
+	// Note this is the list of IDs and not the names. Prior to role creation
+	// the list of policy names gets validated and the policy IDs get stored herein
+	Policies []ACLRolePolicyLink
+
+	// List of service identities whose synthetic policies are granted to
+	// the role.
+	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
+
+	// Labels are arbitrary key/value pairs used to group and select roles
+	Labels map[string]string `json:",omitempty"`
+
+	// Hash of the contents of the role
+	// This does not take into account the ID (which is immutable)
+	// nor the raft metadata.
+	//
+	// This is needed mainly for replication purposes. When replicating from
+	// one DC to another keeping the content Hash will allow us to avoid
+	// unnecessary calls to the authoritative DC
+	Hash []byte
+
+	// Embedded Raft Metadata
+	RaftIndex `hash:"ignore"`
+}
+
+func (r *ACLRole) Clone() *ACLRole {
+	r2 := *r
+	r2.Policies = nil
+	if len(r.Policies) > 0 {
+		r2.Policies = make([]ACLRolePolicyLink, len(r.Policies))
+		copy(r2.Policies, r.Policies)
+	}
+	r2.ServiceIdentities = cloneServiceIdentities(r.ServiceIdentities)
+	r2.Labels = cloneLabels(r.Labels)
+	return &r2
+}
+
+func (r *ACLRole) PolicyIDs() []string {
+	var ids []string
+	for _, link := range r.Policies {
+		ids = append(ids, link.ID)
+	}
+	return ids
+}
+
+func (r *ACLRole) ServiceIdentityList() []*ACLServiceIdentity {
+	return cloneServiceIdentities(r.ServiceIdentities)
+}
+
+func (r *ACLRole) SetHash(force bool) []byte {
+	if force || r.Hash == nil {
+		// Initialize a 256bit Blake2 hash (32 bytes)
+		hash, err := blake2b.New256(nil)
+		if err != nil {
+			panic(err)
+		}
+
+		// Write all the user set fields
+		hash.Write([]byte(r.Name))
+		hash.Write([]byte(r.Description))
+		for _, link := range r.Policies {
+			hash.Write([]byte(link.ID))
+		}
+		for _, s := range r.ServiceIdentities {
+			s.AddToHash(hash)
+		}
+
+		hashLabels(hash, r.Labels)
+
+		// Finalize the hash
+		hashVal := hash.Sum(nil)
+
+		// Set and return the hash
+		r.Hash = hashVal
+	}
+	return r.Hash
+}
+
+func (r *ACLRole) EstimateSize() int {
+	// This is just an estimate. There is other data structure overhead
+	// pointers etc that this does not account for.
+
+	// 60 = 36 (uuid) + 16 (RaftIndex) + 8 (Hash)
+	size := 60 + len(r.Name) + len(r.Description)
+	for _, link := range r.Policies {
+		size += len(link.ID) + len(link.Name)
+	}
+	for _, s := range r.ServiceIdentities {
+		size += s.EstimateSize()
+	}
+	for k, v := range r.Labels {
+		size += len(k) + len(v)
+	}
+
+	return size
+}
+
+type ACLRoles []*ACLRole
+
+// HashKey returns a consistent hash for a set of roles.
+func (roles ACLRoles) HashKey() string {
+	cacheKeyHash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+	for _, role := range roles {
+		cacheKeyHash.Write([]byte(role.ID))
+		// including the modify index prevents a role set from being
+		// cached if one of the roles has changed
+		binary.Write(cacheKeyHash, binary.BigEndian, role.ModifyIndex)
+	}
+	return fmt.Sprintf("%x", cacheKeyHash.Sum(nil))
+}
+
+func (roles ACLRoles) Sort() {
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].ID < roles[j].ID
+	})
+}
+
+// ValidateACLLabels checks the labels of an ACL object. Labels share the key
+// and value constraints of node metadata.
+func ValidateACLLabels(labels map[string]string) error {
+	if len(labels) > metaMaxKeyPairs {
+		return fmt.Errorf("Labels cannot contain more than %d key/value pairs", metaMaxKeyPairs)
+	}
+
+	for key, value := range labels {
+		if err := validateMetaPair(key, value, false); err != nil {
+			return fmt.Errorf("Invalid label ('%s', '%s'): %s", key, value, err)
+		}
+	}
+
+	return nil
+}
+
+func cloneLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	labels2 := make(map[string]string, len(labels))
+	for k, v := range labels {
+		labels2[k] = v
+	}
+	return labels2
+}
+
+// hashLabels writes the labels into the hash in a stable order. Nothing is
+// written for an empty set so that hashes of unlabeled objects are unchanged.
+func hashLabels(hash io.Writer, labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		hash.Write([]byte("label:" + k + "=" + labels[k] + "\x00"))
+	}
+}
+
 type ACLReplicationType string
 
 const (
 	ACLReplicateLegacy   ACLReplicationType = "legacy"
 	ACLReplicatePolicies ACLReplicationType = "policies"
+	ACLReplicateRoles    ACLReplicationType = "roles"
 	ACLReplicateTokens   ACLReplicationType = "tokens"
 )
 
@@ -516,6 +891,7 @@ type ACLReplicationStatus struct {
 	SourceDatacenter     string
 	ReplicationType      ACLReplicationType
 	ReplicatedIndex      uint64
+	ReplicatedRoleIndex  uint64
 	ReplicatedTokenIndex uint64
 	LastSuccess          time.Time
 	LastError            time.Time
@@ -525,6 +901,7 @@ type ACLReplicationStatus struct {
 // at the RPC layer
 type ACLTokenSetRequest struct {
 	ACLToken   ACLToken // Token to manipulate - I really dislike this name but "Token" is taken in the WriteRequest
+	Create     bool     // Whether this is a creation, which allows choosing the AccessorID and SecretID
 	Datacenter string   // The datacenter to perform the request within
 	WriteRequest
 }
@@ -558,10 +935,12 @@ func (r *ACLTokenDeleteRequest) RequestDatacenter() string {
 
 // ACLTokenListRequest is used for token listing operations at the RPC layer
 type ACLTokenListRequest struct {
-	IncludeLocal  bool   // Whether local tokens should be included
-	IncludeGlobal bool   // Whether global tokens should be included
-	Policy        string // Policy filter
-	Datacenter    string // The datacenter to perform the request within
+	IncludeLocal  bool              // Whether local tokens should be included
+	IncludeGlobal bool              // Whether global tokens should be included
+	Policy        string            // Policy filter
+	Role          string            // Role filter
+	Labels        map[string]string // Label filter, all labels must match
+	Datacenter    string            // The datacenter to perform the request within
 	QueryOptions
 }
 
@@ -668,7 +1047,8 @@ func (r *ACLPolicyGetRequest) RequestDatacenter() string {
 
 // ACLPolicyListRequest is used at the RPC layer to request a listing of policies
 type ACLPolicyListRequest struct {
-	Datacenter string // The datacenter to perform the request within
+	Labels     map[string]string // Label filter, all labels must match
+	Datacenter string            // The datacenter to perform the request within
 	QueryOptions
 }
 
@@ -699,6 +1079,19 @@ type ACLPolicyResponse struct {
 	QueryMeta
 }
 
+// ACLPolicyUsage lists the tokens and roles that link to a policy
+type ACLPolicyUsage struct {
+	Tokens ACLTokenListStubs
+	Roles  ACLRoles
+}
+
+// ACLPolicyUsageResponse returns the usage of a single policy + metadata. Usage
+// is nil when the policy doesn't exist.
+type ACLPolicyUsageResponse struct {
+	Usage *ACLPolicyUsage
+	QueryMeta
+}
+
 type ACLPolicyBatchResponse struct {
 	Policies []*ACLPolicy
 	QueryMeta
@@ -719,3 +1112,129 @@ type ACLPolicyBatchSetRequest struct {
 type ACLPolicyBatchDeleteRequest struct {
 	PolicyIDs []string
 }
+
+// ACLRoleSetRequest is used at the RPC layer for creation and update requests
+type ACLRoleSetRequest struct {
+	Role       ACLRole // The role to upsert
+	Datacenter string  // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLRoleSetRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLRoleDeleteRequest is used at the RPC layer deletion requests
+type ACLRoleDeleteRequest struct {
+	RoleID     string // id of the role to delete
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLRoleDeleteRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLRoleGetRequest is used at the RPC layer to perform role read operations
+type ACLRoleGetRequest struct {
+	RoleID     string // id used for the role lookup (one of RoleID or RoleName is allowed)
+	RoleName   string // name used for the role lookup (one of RoleID or RoleName is allowed)
+	Datacenter string // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLRoleGetRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLRoleListRequest is used at the RPC layer to request a listing of roles
+type ACLRoleListRequest struct {
+	Policy     string            // Policy filter
+	Labels     map[string]string // Label filter, all labels must match
+	Datacenter string            // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLRoleListRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+type ACLRoleListResponse struct {
+	Roles ACLRoles
+	QueryMeta
+}
+
+// ACLRoleBatchGetRequest is used at the RPC layer to request a subset of
+// the roles associated with the token used for retrieval
+type ACLRoleBatchGetRequest struct {
+	RoleIDs    []string // List of role ids to fetch
+	Datacenter string   // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLRoleBatchGetRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLRoleResponse returns a single role + metadata
+type ACLRoleResponse struct {
+	Role *ACLRole
+	QueryMeta
+}
+
+type ACLRoleBatchResponse struct {
+	Roles []*ACLRole
+	QueryMeta
+}
+
+// ACLRoleBatchSetRequest is used at the Raft layer for batching
+// multiple role creations and updates
+//
+// This is particularly useful during replication
+type ACLRoleBatchSetRequest struct {
+	Roles             ACLRoles
+	AllowMissingLinks bool // Whether links to unknown policies are kept
+}
+
+// ACLRoleBatchDeleteRequest is used at the Raft layer for batching
+// multiple role deletions
+//
+// This is particularly useful during replication
+type ACLRoleBatchDeleteRequest struct {
+	RoleIDs []string
+}
+
+// ACLAuthorizeRequest is used at the RPC layer to check whether a token
+// is granted access to a resource
+type ACLAuthorizeRequest struct {
+	AccessorID string // Accessor ID of the token to check, the request token if empty
+	Resource   string // The resource to check access to, such as "key" or "service"
+	Segment    string // The name of the object within the resource
+	Access     string // The access level to check, one of "read", "list" or "write"
+	Datacenter string // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLAuthorizeRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLAuthorization is the result of checking whether a token is granted
+// access to a resource
+type ACLAuthorization struct {
+	Resource string
+	Segment  string
+	Access   string
+	Allow    bool
+
+	// Rule is the policy rule that made the decision. It is empty when no
+	// rule applies and the decision is made by DefaultPolicy.
+	Rule          string
+	DefaultPolicy string
+}
+
+// ACLAuthorizeResponse returns the result of an authorization check
+type ACLAuthorizeResponse struct {
+	Authorization ACLAuthorization
+	QueryMeta
+}
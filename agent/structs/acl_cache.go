@@ -1,24 +1,239 @@
 package structs
 
 import (
+	"sync"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/consul/acl"
-	"github.com/hashicorp/golang-lru"
+	"github.com/hashicorp/golang-lru/simplelru"
 )
 
+// aclCacheEntryOverhead approximates the bookkeeping cost of a single cache
+// entry and is used as the size of values that can't be estimated.
+const aclCacheEntryOverhead = 64
+
 type ACLCachesConfig struct {
 	Identities     int
 	Policies       int
 	ParsedPolicies int
 	Authorizers    int
+	Roles          int
+
+	// The *Bytes limits additionally bound a cache by the estimated size of
+	// its entries. Zero means the cache is only bounded by entry count.
+	// Compiled authorizers can't be sized and so are only bounded by count.
+	IdentitiesBytes     int
+	PoliciesBytes       int
+	ParsedPoliciesBytes int
+	RolesBytes          int
 }
 
 type ACLCaches struct {
-	identities     *lru.TwoQueueCache // identity id -> structs.ACLIdentity
-	parsedPolicies *lru.TwoQueueCache // policy content hash -> acl.Policy
-	policies       *lru.TwoQueueCache // policy ID -> ACLPolicy
-	authorizers    *lru.TwoQueueCache // token secret -> acl.Authorizer
+	identities     *aclCache // identity id -> structs.ACLIdentity
+	parsedPolicies *aclCache // policy content hash -> acl.Policy
+	policies       *aclCache // policy ID -> ACLPolicy
+	authorizers    *aclCache // token secret -> acl.Authorizer
+	roles          *aclCache // role ID -> ACLRole
+}
+
+// aclCache is an LRU cache bounded both by number of entries and by the
+// estimated size of the cached values. It emits hit, miss and eviction
+// counters along with entry and byte gauges under acl.cache.<name>.
+type aclCache struct {
+	name     string
+	sizeOf   func(value interface{}) int
+	maxBytes int
+
+	lock  sync.Mutex
+	lru   *simplelru.LRU
+	bytes int
+}
+
+type aclCacheItem struct {
+	value interface{}
+	size  int
+}
+
+func newACLCache(name string, size, maxBytes int, sizeOf func(interface{}) int) (*aclCache, error) {
+	c := &aclCache{
+		name:     name,
+		sizeOf:   sizeOf,
+		maxBytes: maxBytes,
+	}
+
+	lru, err := simplelru.NewLRU(size, func(_ interface{}, value interface{}) {
+		c.bytes -= value.(*aclCacheItem).size
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+func (c *aclCache) Get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	raw, ok := c.lru.Get(key)
+	c.lock.Unlock()
+
+	if !ok {
+		metrics.IncrCounter([]string{"acl", "cache", c.name, "miss"}, 1)
+		return nil, false
+	}
+	metrics.IncrCounter([]string{"acl", "cache", c.name, "hit"}, 1)
+	return raw.(*aclCacheItem).value, true
+}
+
+func (c *aclCache) Add(key string, value interface{}) {
+	item := &aclCacheItem{value: value, size: c.sizeOf(value) + len(key)}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Replacing a value doesn't go through the eviction callback.
+	if old, ok := c.lru.Peek(key); ok {
+		c.bytes -= old.(*aclCacheItem).size
+	}
+
+	evicted := 0
+	if c.lru.Add(key, item) {
+		evicted++
+	}
+	c.bytes += item.size
+
+	// Always keep the newest entry, even if it alone exceeds the limit.
+	for c.maxBytes > 0 && c.bytes > c.maxBytes && c.lru.Len() > 1 {
+		c.lru.RemoveOldest()
+		evicted++
+	}
+
+	if evicted > 0 {
+		metrics.IncrCounter([]string{"acl", "cache", c.name, "evict"}, float32(evicted))
+	}
+	c.emitGauges()
+}
+
+func (c *aclCache) Remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lru.Remove(key)
+	c.emitGauges()
+}
+
+func (c *aclCache) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lru.Purge()
+	c.bytes = 0
+	c.emitGauges()
+}
+
+// Len returns the number of entries and their estimated size in bytes.
+func (c *aclCache) Len() (int, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Len(), c.bytes
+}
+
+// emitGauges must be called with the lock held.
+func (c *aclCache) emitGauges() {
+	metrics.SetGauge([]string{"acl", "cache", c.name, "entries"}, float32(c.lru.Len()))
+	metrics.SetGauge([]string{"acl", "cache", c.name, "bytes"}, float32(c.bytes))
+}
+
+func identityCacheEntrySize(value interface{}) int {
+	size := aclCacheEntryOverhead
+	if sizer, ok := value.(*IdentityCacheEntry).Identity.(interface{ EstimateSize() int }); ok {
+		size += sizer.EstimateSize()
+	}
+	return size
+}
+
+func policyCacheEntrySize(value interface{}) int {
+	size := aclCacheEntryOverhead
+	if policy := value.(*PolicyCacheEntry).Policy; policy != nil {
+		size += policy.EstimateSize()
+	}
+	return size
+}
+
+func parsedPolicyCacheEntrySize(value interface{}) int {
+	size := aclCacheEntryOverhead
+	if policy := value.(*ParsedPolicyCacheEntry).Policy; policy != nil {
+		size += estimateParsedPolicySize(policy)
+	}
+	return size
+}
+
+func roleCacheEntrySize(value interface{}) int {
+	size := aclCacheEntryOverhead
+	if role := value.(*RoleCacheEntry).Role; role != nil {
+		size += role.EstimateSize()
+	}
+	return size
+}
+
+func authorizerCacheEntrySize(value interface{}) int {
+	return aclCacheEntryOverhead
+}
+
+// estimateParsedPolicySize approximates the memory held by a parsed policy
+// from the length of its rule segments and policy strings.
+func estimateParsedPolicySize(p *acl.Policy) int {
+	size := len(p.ID) + len(p.ACL) + len(p.Keyring) + len(p.Operator)
+	rule := func(segment, policy string) {
+		size += aclCacheEntryOverhead/4 + len(segment) + len(policy)
+	}
+
+	for _, r := range p.Agents {
+		rule(r.Node, r.Policy)
+	}
+	for _, r := range p.AgentPrefixes {
+		rule(r.Node, r.Policy)
+	}
+	for _, r := range p.Keys {
+		rule(r.Prefix, r.Policy)
+	}
+	for _, r := range p.KeyPrefixes {
+		rule(r.Prefix, r.Policy)
+	}
+	for _, r := range p.Nodes {
+		rule(r.Name, r.Policy)
+	}
+	for _, r := range p.NodePrefixes {
+		rule(r.Name, r.Policy)
+	}
+	for _, r := range p.Services {
+		rule(r.Name, r.Policy)
+		size += len(r.Intentions)
+	}
+	for _, r := range p.ServicePrefixes {
+		rule(r.Name, r.Policy)
+		size += len(r.Intentions)
+	}
+	for _, r := range p.Sessions {
+		rule(r.Node, r.Policy)
+	}
+	for _, r := range p.SessionPrefixes {
+		rule(r.Node, r.Policy)
+	}
+	for _, r := range p.Events {
+		rule(r.Event, r.Policy)
+	}
+	for _, r := range p.EventPrefixes {
+		rule(r.Event, r.Policy)
+	}
+	for _, r := range p.PreparedQueries {
+		rule(r.Prefix, r.Policy)
+	}
+	for _, r := range p.PreparedQueryPrefixes {
+		rule(r.Prefix, r.Policy)
+	}
+	return size
 }
 
 type IdentityCacheEntry struct {
@@ -48,6 +263,15 @@ func (e *PolicyCacheEntry) Age() time.Duration {
 	return time.Since(e.CacheTime)
 }
 
+type RoleCacheEntry struct {
+	Role      *ACLRole
+	CacheTime time.Time
+}
+
+func (e *RoleCacheEntry) Age() time.Duration {
+	return time.Since(e.CacheTime)
+}
+
 type AuthorizerCacheEntry struct {
 	Authorizer acl.Authorizer
 	CacheTime  time.Time
@@ -62,7 +286,7 @@ func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 	cache := &ACLCaches{}
 
 	if config != nil && config.Identities > 0 {
-		identCache, err := lru.New2Q(config.Identities)
+		identCache, err := newACLCache("identities", config.Identities, config.IdentitiesBytes, identityCacheEntrySize)
 		if err != nil {
 			return nil, err
 		}
@@ -71,7 +295,7 @@ func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 	}
 
 	if config != nil && config.Policies > 0 {
-		policyCache, err := lru.New2Q(config.Policies)
+		policyCache, err := newACLCache("policies", config.Policies, config.PoliciesBytes, policyCacheEntrySize)
 		if err != nil {
 			return nil, err
 		}
@@ -80,7 +304,7 @@ func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 	}
 
 	if config != nil && config.ParsedPolicies > 0 {
-		parsedCache, err := lru.New2Q(config.ParsedPolicies)
+		parsedCache, err := newACLCache("parsed_policies", config.ParsedPolicies, config.ParsedPoliciesBytes, parsedPolicyCacheEntrySize)
 		if err != nil {
 			return nil, err
 		}
@@ -89,7 +313,7 @@ func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 	}
 
 	if config != nil && config.Authorizers > 0 {
-		authCache, err := lru.New2Q(config.Authorizers)
+		authCache, err := newACLCache("authorizers", config.Authorizers, 0, authorizerCacheEntrySize)
 		if err != nil {
 			return nil, err
 		}
@@ -97,6 +321,15 @@ func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 		cache.authorizers = authCache
 	}
 
+	if config != nil && config.Roles > 0 {
+		roleCache, err := newACLCache("roles", config.Roles, config.RolesBytes, roleCacheEntrySize)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.roles = roleCache
+	}
+
 	return cache, nil
 }
 
@@ -139,6 +372,19 @@ func (c *ACLCaches) GetParsedPolicy(id string) *ParsedPolicyCacheEntry {
 	return nil
 }
 
+// GetRole fetches a role from the cache and returns it
+func (c *ACLCaches) GetRole(roleID string) *RoleCacheEntry {
+	if c == nil || c.roles == nil {
+		return nil
+	}
+
+	if raw, ok := c.roles.Get(roleID); ok {
+		return raw.(*RoleCacheEntry)
+	}
+
+	return nil
+}
+
 // GetAuthorizer fetches a acl from the cache and returns it
 func (c *ACLCaches) GetAuthorizer(id string) *AuthorizerCacheEntry {
 	if c == nil || c.authorizers == nil {
@@ -177,6 +423,14 @@ func (c *ACLCaches) PutParsedPolicy(id string, policy *acl.Policy) {
 	c.parsedPolicies.Add(id, &ParsedPolicyCacheEntry{Policy: policy, CacheTime: time.Now()})
 }
 
+func (c *ACLCaches) PutRole(roleID string, role *ACLRole) {
+	if c == nil || c.roles == nil {
+		return
+	}
+
+	c.roles.Add(roleID, &RoleCacheEntry{Role: role, CacheTime: time.Now()})
+}
+
 func (c *ACLCaches) PutAuthorizer(id string, authorizer acl.Authorizer) {
 	if c == nil || c.authorizers == nil {
 		return
@@ -205,6 +459,12 @@ func (c *ACLCaches) RemovePolicy(policyID string) {
 	}
 }
 
+func (c *ACLCaches) RemoveRole(roleID string) {
+	if c != nil && c.roles != nil {
+		c.roles.Remove(roleID)
+	}
+}
+
 func (c *ACLCaches) Purge() {
 	if c != nil {
 		if c.identities != nil {
@@ -219,5 +479,8 @@ func (c *ACLCaches) Purge() {
 		if c.authorizers != nil {
 			c.authorizers.Purge()
 		}
+		if c.roles != nil {
+			c.roles.Purge()
+		}
 	}
 }
@@ -16,7 +16,12 @@ func TestStructs_ACLCaches(t *testing.T) {
 		t.Run("Valid Sizes", func(t *testing.T) {
 			t.Parallel()
 			// 1 isn't valid due to a bug in golang-lru library
-			config := ACLCachesConfig{2, 2, 2, 2}
+			config := ACLCachesConfig{
+				Identities:     2,
+				Policies:       2,
+				ParsedPolicies: 2,
+				Authorizers:    2,
+			}
 
 			cache, err := NewACLCaches(&config)
 			require.NoError(t, err)
@@ -30,7 +35,7 @@ func TestStructs_ACLCaches(t *testing.T) {
 		t.Run("Zero Sizes", func(t *testing.T) {
 			t.Parallel()
 			// 1 isn't valid due to a bug in golang-lru library
-			config := ACLCachesConfig{0, 0, 0, 0}
+			config := ACLCachesConfig{}
 
 			cache, err := NewACLCaches(&config)
 			require.NoError(t, err)
@@ -102,4 +107,67 @@ func TestStructs_ACLCaches(t *testing.T) {
 		require.NotNil(t, entry.Authorizer)
 		require.True(t, entry.Authorizer == acl.DenyAll())
 	})
+	t.Run("Byte Limit", func(t *testing.T) {
+		t.Parallel()
+
+		token := &ACLToken{
+			AccessorID: "09d1c059-961a-46bd-a2e4-76adebe35fa5",
+			SecretID:   "65e98e67-9b29-470c-8ffa-7c5a23cc67c8",
+			Rules:      `key "" { policy = "read" }`,
+		}
+		entrySize := identityCacheEntrySize(&IdentityCacheEntry{Identity: token}) + len("foo")
+
+		config := ACLCachesConfig{
+			Identities:      16,
+			IdentitiesBytes: 2*entrySize + entrySize/2,
+		}
+
+		cache, err := NewACLCaches(&config)
+		require.NoError(t, err)
+
+		cache.PutIdentity("foo", token)
+		cache.PutIdentity("bar", token)
+		entries, bytes := cache.identities.Len()
+		require.Equal(t, 2, entries)
+		require.Equal(t, 2*entrySize, bytes)
+
+		// Reading foo makes bar the least recently used entry.
+		require.NotNil(t, cache.GetIdentity("foo"))
+		cache.PutIdentity("baz", token)
+		require.NotNil(t, cache.GetIdentity("foo"))
+		require.Nil(t, cache.GetIdentity("bar"))
+		require.NotNil(t, cache.GetIdentity("baz"))
+
+		// Replacing an entry must not leak its old size.
+		cache.PutIdentity("foo", nil)
+		_, bytes = cache.identities.Len()
+		require.Equal(t, entrySize+aclCacheEntryOverhead+len("foo"), bytes)
+
+		cache.RemoveIdentity("baz")
+		entries, bytes = cache.identities.Len()
+		require.Equal(t, 1, entries)
+		require.Equal(t, aclCacheEntryOverhead+len("foo"), bytes)
+
+		cache.Purge()
+		entries, bytes = cache.identities.Len()
+		require.Equal(t, 0, entries)
+		require.Equal(t, 0, bytes)
+	})
+
+	t.Run("Byte Limit Keeps Newest", func(t *testing.T) {
+		t.Parallel()
+
+		config := ACLCachesConfig{
+			Policies:      4,
+			PoliciesBytes: 1,
+		}
+
+		cache, err := NewACLCaches(&config)
+		require.NoError(t, err)
+
+		cache.PutPolicy("foo", &ACLPolicy{Rules: `node "" { policy = "read" }`})
+		cache.PutPolicy("bar", &ACLPolicy{Rules: `node "" { policy = "write" }`})
+		require.Nil(t, cache.GetPolicy("foo"))
+		require.NotNil(t, cache.GetPolicy("bar"))
+	})
 }
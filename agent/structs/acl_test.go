@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/acl"
 
@@ -140,6 +141,28 @@ func TestStructs_ACLToken_EmbeddedPolicy(t *testing.T) {
 	})
 }
 
+func TestStructs_ACLToken_IsExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	expiration := now.Add(time.Minute)
+
+	token := &ACLToken{}
+	require.False(t, token.HasExpirationTime())
+	require.False(t, token.IsExpired(now))
+
+	token.ExpirationTime = &time.Time{}
+	require.False(t, token.HasExpirationTime())
+	require.False(t, token.IsExpired(now))
+
+	token.ExpirationTime = &expiration
+	require.True(t, token.HasExpirationTime())
+	require.False(t, token.IsExpired(now))
+	require.False(t, token.IsExpired(expiration))
+	require.True(t, token.IsExpired(expiration.Add(time.Nanosecond)))
+	require.False(t, token.IsExpired(time.Time{}))
+}
+
 func TestStructs_ACLToken_SetHash(t *testing.T) {
 	t.Parallel()
 
@@ -185,6 +208,97 @@ func TestStructs_ACLToken_SetHash(t *testing.T) {
 	})
 }
 
+func TestStructs_ACLToken_SetHash_serviceIdentities(t *testing.T) {
+	t.Parallel()
+
+	token := ACLToken{
+		ServiceIdentities: []*ACLServiceIdentity{
+			&ACLServiceIdentity{ServiceName: "web"},
+		},
+	}
+	original := token.SetHash(true)
+
+	token.ServiceIdentities[0].Datacenters = []string{"dc1"}
+	require.NotEqual(t, original, token.SetHash(true))
+
+	token.ServiceIdentities = nil
+	require.NotEqual(t, original, token.SetHash(true))
+}
+
+func TestStructs_ACLServiceIdentity_SyntheticPolicy(t *testing.T) {
+	t.Parallel()
+
+	s := &ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}}
+	policy := s.SyntheticPolicy()
+
+	require.Equal(t, []string{"dc1"}, policy.Datacenters)
+	require.Contains(t, policy.Rules, `service "web" {`)
+	require.Contains(t, policy.Rules, `service "web-sidecar-proxy" {`)
+	require.NotEmpty(t, policy.Hash)
+
+	// The same service and datacenters always yield the same policy ID while
+	// other services or datacenters do not.
+	require.Len(t, policy.ID, 32)
+	require.Equal(t, policy.ID, (&ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}}).SyntheticPolicy().ID)
+	require.NotEqual(t, policy.ID, (&ACLServiceIdentity{ServiceName: "web"}).SyntheticPolicy().ID)
+	require.NotEqual(t, policy.ID, (&ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc2"}}).SyntheticPolicy().ID)
+	require.NotEqual(t, policy.ID, (&ACLServiceIdentity{ServiceName: "db", Datacenters: []string{"dc1"}}).SyntheticPolicy().ID)
+
+	parsed, err := acl.NewPolicyFromSource("", 0, policy.Rules, acl.SyntaxCurrent, nil)
+	require.NoError(t, err)
+	require.Len(t, parsed.Services, 2)
+}
+
+func TestStructs_ACLToken_SetHash_labels(t *testing.T) {
+	t.Parallel()
+
+	token := ACLToken{
+		AccessorID:  "09d1c059-961a-46bd-a2e4-76adebe35fa5",
+		SecretID:    "65e98e67-9b29-470c-8ffa-7c5a23cc67c8",
+		Description: "test",
+	}
+
+	unlabeled := token.SetHash(true)
+
+	token.Labels = map[string]string{}
+	require.Equal(t, unlabeled, token.SetHash(true))
+
+	token.Labels = map[string]string{"team": "payments", "env": "prod"}
+	labeled := token.SetHash(true)
+	require.NotEqual(t, unlabeled, labeled)
+
+	token.Labels = map[string]string{"env": "prod", "team": "payments"}
+	require.Equal(t, labeled, token.SetHash(true))
+
+	token.Labels["env"] = "staging"
+	require.NotEqual(t, labeled, token.SetHash(true))
+}
+
+func TestStructs_ValidateACLLabels(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateACLLabels(nil))
+	require.NoError(t, ValidateACLLabels(map[string]string{"team": "payments"}))
+
+	err := ValidateACLLabels(map[string]string{"": "payments"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid label")
+
+	err = ValidateACLLabels(map[string]string{"team!": "payments"})
+	require.Error(t, err)
+
+	err = ValidateACLLabels(map[string]string{"consul-team": "payments"})
+	require.Error(t, err)
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= metaMaxKeyPairs; i++ {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+	err = ValidateACLLabels(tooMany)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot contain more than")
+}
+
 func TestStructs_ACLToken_EstimateSize(t *testing.T) {
 	t.Parallel()
 
@@ -208,7 +322,7 @@ func TestStructs_ACLToken_EstimateSize(t *testing.T) {
 
 	// this test is very contrived. Basically just tests that the
 	// math is okay and returns the value.
-	require.Equal(t, 120, token.EstimateSize())
+	require.Equal(t, 128, token.EstimateSize())
 }
 
 func TestStructs_ACLToken_Stub(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"time"
 )
 
@@ -20,17 +21,35 @@ type ACLTokenPolicyLink struct {
 	Name string
 }
 
+type ACLTokenRoleLink struct {
+	ID   string
+	Name string
+}
+
+// ACLServiceIdentity represents a high-level grant of all necessary privileges
+// to assume the identity of the named service in the Catalog and within
+// Connect.
+type ACLServiceIdentity struct {
+	ServiceName string
+	Datacenters []string `json:",omitempty"`
+}
+
 // ACLToken represents an ACL Token
 type ACLToken struct {
-	CreateIndex uint64
-	ModifyIndex uint64
-	AccessorID  string
-	SecretID    string
-	Description string
-	Policies    []*ACLTokenPolicyLink
-	Local       bool
-	CreateTime  time.Time `json:",omitempty"`
-	Hash        []byte    `json:",omitempty"`
+	CreateIndex       uint64
+	ModifyIndex       uint64
+	AccessorID        string
+	SecretID          string
+	Description       string
+	Policies          []*ACLTokenPolicyLink
+	Roles             []*ACLTokenRoleLink   `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
+	Local             bool
+	CreateTime        time.Time         `json:",omitempty"`
+	ExpirationTime    *time.Time        `json:",omitempty"`
+	ExpirationTTL     time.Duration     `json:",omitempty"`
+	Labels            map[string]string `json:",omitempty"`
+	Hash              []byte            `json:",omitempty"`
 
 	// DEPRECATED (ACL-Legacy-Compat)
 	// Rules will only be present for legacy tokens returned via the new APIs
@@ -38,15 +57,19 @@ type ACLToken struct {
 }
 
 type ACLTokenListEntry struct {
-	CreateIndex uint64
-	ModifyIndex uint64
-	AccessorID  string
-	Description string
-	Policies    []*ACLTokenPolicyLink
-	Local       bool
-	CreateTime  time.Time
-	Hash        []byte
-	Legacy      bool
+	CreateIndex       uint64
+	ModifyIndex       uint64
+	AccessorID        string
+	Description       string
+	Policies          []*ACLTokenPolicyLink
+	Roles             []*ACLTokenRoleLink   `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
+	Local             bool
+	CreateTime        time.Time
+	ExpirationTime    *time.Time        `json:",omitempty"`
+	Labels            map[string]string `json:",omitempty"`
+	Hash              []byte
+	Legacy            bool
 }
 
 // ACLEntry is used to represent a legacy ACL token
@@ -67,11 +90,26 @@ type ACLReplicationStatus struct {
 	SourceDatacenter     string
 	ReplicationType      string
 	ReplicatedIndex      uint64
+	ReplicatedRoleIndex  uint64
 	ReplicatedTokenIndex uint64
 	LastSuccess          time.Time
 	LastError            time.Time
 }
 
+// ACLAuthorization is the result of checking whether a token is granted
+// some access to a resource.
+type ACLAuthorization struct {
+	Resource string
+	Segment  string
+	Access   string
+	Allow    bool
+
+	// Rule is the policy rule that made the decision. It is empty when no
+	// rule applies and the decision is made by DefaultPolicy.
+	Rule          string
+	DefaultPolicy string
+}
+
 // ACLPolicy represents an ACL Policy.
 type ACLPolicy struct {
 	ID          string
@@ -79,6 +117,7 @@ type ACLPolicy struct {
 	Description string
 	Rules       string
 	Datacenters []string
+	Labels      map[string]string `json:",omitempty"`
 	Hash        []byte
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -89,11 +128,36 @@ type ACLPolicyListEntry struct {
 	Name        string
 	Description string
 	Datacenters []string
+	Labels      map[string]string `json:",omitempty"`
 	Hash        []byte
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ACLPolicyUsage lists the tokens and roles that link to a policy.
+type ACLPolicyUsage struct {
+	Tokens []*ACLTokenListEntry
+	Roles  []*ACLRole
+}
+
+type ACLRolePolicyLink struct {
+	ID   string
+	Name string
+}
+
+// ACLRole represents an ACL Role.
+type ACLRole struct {
+	ID                string
+	Name              string
+	Description       string
+	Policies          []*ACLRolePolicyLink
+	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
+	Labels            map[string]string     `json:",omitempty"`
+	Hash              []byte
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
 // ACL can be used to query the ACL endpoints
 type ACL struct {
 	c *Client
@@ -277,6 +341,19 @@ func (a *ACL) TokenCreate(token *ACLToken, q *WriteOptions) (*ACLToken, *WriteMe
 		return nil, nil, fmt.Errorf("Cannot specify a SecretID in Token Creation")
 	}
 
+	return a.tokenCreate(token, q)
+}
+
+// TokenCreateWithIDs creates a new ACL token keeping the AccessorID and SecretID
+// set in the ACLToken structure, for example to migrate tokens from another cluster.
+// Either may be left empty to have Consul generate it. Both must be UUIDs not yet
+// in use. Tokens should otherwise be created with TokenCreate, which never lets a
+// secret be chosen by the caller.
+func (a *ACL) TokenCreateWithIDs(token *ACLToken, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	return a.tokenCreate(token, q)
+}
+
+func (a *ACL) tokenCreate(token *ACLToken, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
 	r := a.c.newRequest("PUT", "/v1/acl/token")
 	r.setWriteOptions(q)
 	r.obj = token
@@ -431,6 +508,44 @@ func (a *ACL) TokenList(q *QueryOptions) ([]*ACLTokenListEntry, *QueryMeta, erro
 	return entries, qm, nil
 }
 
+// ACLTokenFilterOptions restricts a token listing to the tokens linked with a
+// particular policy or role. At most one of the fields may be set.
+type ACLTokenFilterOptions struct {
+	// Policy is the ID of a policy the tokens must be linked with.
+	Policy string
+
+	// Role is the ID of a role the tokens must be linked with.
+	Role string
+}
+
+// TokenListFiltered lists the tokens that match the given filter, which is
+// evaluated by the servers rather than by filtering the full token list.
+func (a *ACL) TokenListFiltered(filter ACLTokenFilterOptions, q *QueryOptions) ([]*ACLTokenListEntry, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/tokens")
+	r.setQueryOptions(q)
+	if filter.Policy != "" {
+		r.params.Set("policy", filter.Policy)
+	}
+	if filter.Role != "" {
+		r.params.Set("role", filter.Role)
+	}
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var entries []*ACLTokenListEntry
+	if err := decodeBody(resp, &entries); err != nil {
+		return nil, nil, err
+	}
+	return entries, qm, nil
+}
+
 // PolicyCreate will create a new policy. It is not allowed for the policy parameters
 // ID field to be set as this will be generated by Consul while processing the request.
 func (a *ACL) PolicyCreate(policy *ACLPolicy, q *WriteOptions) (*ACLPolicy, *WriteMeta, error) {
@@ -539,6 +654,146 @@ func (a *ACL) PolicyList(q *QueryOptions) ([]*ACLPolicyListEntry, *QueryMeta, er
 	return entries, qm, nil
 }
 
+// PolicyUsage retrieves the tokens and roles that link to the policy with the
+// given ID. Tokens that only receive the policy through one of their roles are
+// not included, and neither are local tokens of other datacenters.
+func (a *ACL) PolicyUsage(policyID string, q *QueryOptions) (*ACLPolicyUsage, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/policy/usage/"+policyID)
+	r.setQueryOptions(q)
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out ACLPolicyUsage
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, qm, nil
+}
+
+// RoleCreate will create a new role. It is not allowed for the role parameters
+// ID field to be set as this will be generated by Consul while processing the request.
+func (a *ACL) RoleCreate(role *ACLRole, q *WriteOptions) (*ACLRole, *WriteMeta, error) {
+	if role.ID != "" {
+		return nil, nil, fmt.Errorf("Cannot specify an ID in Role Creation")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/role")
+	r.setWriteOptions(q)
+	r.obj = role
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLRole
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
+// RoleUpdate updates a role. The ID field of the role parameter must be set to an
+// existing role ID
+func (a *ACL) RoleUpdate(role *ACLRole, q *WriteOptions) (*ACLRole, *WriteMeta, error) {
+	if role.ID == "" {
+		return nil, nil, fmt.Errorf("Must specify an ID in Role Update")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/role/"+role.ID)
+	r.setWriteOptions(q)
+	r.obj = role
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLRole
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
+// RoleDelete deletes a role given its ID.
+func (a *ACL) RoleDelete(roleID string, q *WriteOptions) (*WriteMeta, error) {
+	r := a.c.newRequest("DELETE", "/v1/acl/role/"+roleID)
+	r.setWriteOptions(q)
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	return wm, nil
+}
+
+// RoleRead retrieves the role details (by ID).
+func (a *ACL) RoleRead(roleID string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	return a.roleRead("/v1/acl/role/"+roleID, q)
+}
+
+// RoleReadByName retrieves the role details (by name).
+func (a *ACL) RoleReadByName(roleName string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	return a.roleRead("/v1/acl/role/name/"+url.QueryEscape(roleName), q)
+}
+
+func (a *ACL) roleRead(endpoint string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	r := a.c.newRequest("GET", endpoint)
+	r.setQueryOptions(q)
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out ACLRole
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, qm, nil
+}
+
+// RoleList retrieves a listing of all roles.
+func (a *ACL) RoleList(q *QueryOptions) ([]*ACLRole, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/roles")
+	r.setQueryOptions(q)
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var entries []*ACLRole
+	if err := decodeBody(resp, &entries); err != nil {
+		return nil, nil, err
+	}
+	return entries, qm, nil
+}
+
 // RulesTranslate translates the legacy rule syntax into the current syntax.
 //
 // Deprecated: Support for the legacy syntax translation will be removed
@@ -586,3 +841,33 @@ func (a *ACL) RulesTranslateToken(tokenID string) (string, error) {
 
 	return string(ruleBytes), nil
 }
+
+// Authorize checks whether a token is granted the access level ("read",
+// "list" or "write") to the segment of a resource, such as a key or a
+// service name. The token is identified by its accessor ID, an empty ID
+// checks the token used to make the request.
+func (a *ACL) Authorize(accessorID, resource, segment, access string, q *QueryOptions) (*ACLAuthorization, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/authorize")
+	r.setQueryOptions(q)
+	if accessorID != "" {
+		r.params.Set("accessor", accessorID)
+	}
+	r.params.Set("resource", resource)
+	r.params.Set("segment", segment)
+	r.params.Set("access", access)
+	rtt, resp, err := requireOK(a.c.doRequest(r))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out ACLAuthorization
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
@@ -376,6 +376,164 @@ func prepTokenPolicies(t *testing.T, acl *ACL) (policies []*ACLPolicy) {
 	return
 }
 
+func TestAPI_ACLRole_CreateReadUpdateDelete(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	policy, _, err := acl.PolicyCreate(&ACLPolicy{
+		Name:  "test-policy",
+		Rules: `node_prefix "" { policy = "read" }`,
+	}, nil)
+	require.NoError(t, err)
+
+	created, wm, err := acl.RoleCreate(&ACLRole{
+		Name:        "test-role",
+		Description: "test-role description",
+		Policies: []*ACLRolePolicyLink{
+			&ACLRolePolicyLink{
+				Name: policy.Name,
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	require.NotEqual(t, "", created.ID)
+	require.NotEqual(t, 0, wm.RequestTime)
+	require.Len(t, created.Policies, 1)
+	require.Equal(t, policy.ID, created.Policies[0].ID)
+
+	read, qm, err := acl.RoleRead(created.ID, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, 0, qm.LastIndex)
+	require.True(t, qm.KnownLeader)
+	require.Equal(t, created, read)
+
+	read, _, err = acl.RoleReadByName("test-role", nil)
+	require.NoError(t, err)
+	require.Equal(t, created, read)
+
+	read.Description = "updated"
+	read.Policies = nil
+	updated, _, err := acl.RoleUpdate(read, nil)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, updated.ID)
+	require.Equal(t, "updated", updated.Description)
+	require.Empty(t, updated.Policies)
+	require.Equal(t, created.CreateIndex, updated.CreateIndex)
+	require.NotEqual(t, created.ModifyIndex, updated.ModifyIndex)
+
+	roles, _, err := acl.RoleList(nil)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	require.Equal(t, updated, roles[0])
+
+	wm, err = acl.RoleDelete(created.ID, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, 0, wm.RequestTime)
+
+	read, _, err = acl.RoleRead(created.ID, nil)
+	require.Nil(t, read)
+	require.Error(t, err)
+}
+
+func TestAPI_ACLToken_Roles(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	role, _, err := acl.RoleCreate(&ACLRole{Name: "test-role"}, nil)
+	require.NoError(t, err)
+
+	created, _, err := acl.TokenCreate(&ACLToken{
+		Description: "token with role",
+		Roles: []*ACLTokenRoleLink{
+			&ACLTokenRoleLink{
+				Name: role.Name,
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, created.Roles, 1)
+	require.Equal(t, role.ID, created.Roles[0].ID)
+
+	read, _, err := acl.TokenRead(created.AccessorID, nil)
+	require.NoError(t, err)
+	require.Equal(t, created.Roles, read.Roles)
+}
+
+func TestAPI_ACLPolicy_Usage(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	policy, _, err := acl.PolicyCreate(&ACLPolicy{Name: "test-policy"}, nil)
+	require.NoError(t, err)
+
+	role, _, err := acl.RoleCreate(&ACLRole{
+		Name:     "test-role",
+		Policies: []*ACLRolePolicyLink{&ACLRolePolicyLink{ID: policy.ID}},
+	}, nil)
+	require.NoError(t, err)
+
+	token, _, err := acl.TokenCreate(&ACLToken{
+		Description: "token with policy",
+		Policies:    []*ACLTokenPolicyLink{&ACLTokenPolicyLink{ID: policy.ID}},
+	}, nil)
+	require.NoError(t, err)
+
+	usage, qm, err := acl.PolicyUsage(policy.ID, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, 0, qm.LastIndex)
+	require.Len(t, usage.Tokens, 1)
+	require.Equal(t, token.AccessorID, usage.Tokens[0].AccessorID)
+	require.Len(t, usage.Roles, 1)
+	require.Equal(t, role.ID, usage.Roles[0].ID)
+}
+
+func TestAPI_ACLToken_ServiceIdentities(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	created, _, err := acl.TokenCreate(&ACLToken{
+		Description: "token with service identity",
+		ServiceIdentities: []*ACLServiceIdentity{
+			&ACLServiceIdentity{
+				ServiceName: "web",
+				Datacenters: []string{"dc1"},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*ACLServiceIdentity{
+		&ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+	}, created.ServiceIdentities)
+
+	read, _, err := acl.TokenRead(created.AccessorID, nil)
+	require.NoError(t, err)
+	require.Equal(t, created.ServiceIdentities, read.ServiceIdentities)
+
+	role, _, err := acl.RoleCreate(&ACLRole{
+		Name: "web",
+		ServiceIdentities: []*ACLServiceIdentity{
+			&ACLServiceIdentity{ServiceName: "web"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*ACLServiceIdentity{
+		&ACLServiceIdentity{ServiceName: "web"},
+	}, role.ServiceIdentities)
+}
+
 func TestAPI_ACLToken_CreateReadDelete(t *testing.T) {
 	t.Parallel()
 	c, s := makeACLClient(t)
@@ -429,6 +587,35 @@ func TestAPI_ACLToken_CreateReadDelete(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestAPI_ACLToken_CreateWithIDs(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	token := &ACLToken{
+		AccessorID:  "6a1253d2-1785-24fd-91c2-f8e78c745511",
+		SecretID:    "45a3bd52-07c7-47a4-52fd-0745e0cfe967",
+		Description: "migrated",
+	}
+
+	// TokenCreate refuses caller-chosen IDs.
+	_, _, err := acl.TokenCreate(token, nil)
+	require.EqualError(t, err, "Cannot specify an AccessorID in Token Creation")
+	_, _, err = acl.TokenCreate(&ACLToken{SecretID: token.SecretID}, nil)
+	require.EqualError(t, err, "Cannot specify a SecretID in Token Creation")
+
+	created, _, err := acl.TokenCreateWithIDs(token, nil)
+	require.NoError(t, err)
+	require.Equal(t, token.AccessorID, created.AccessorID)
+	require.Equal(t, token.SecretID, created.SecretID)
+
+	// The IDs may not be reused.
+	_, _, err = acl.TokenCreateWithIDs(token, nil)
+	require.Error(t, err)
+}
+
 func TestAPI_ACLToken_CreateUpdate(t *testing.T) {
 	t.Parallel()
 	c, s := makeACLClient(t)
@@ -594,6 +781,33 @@ func TestAPI_ACLToken_List(t *testing.T) {
 	token5, ok := tokenMap[root.AccessorID]
 	require.True(t, ok)
 	require.NotNil(t, token5)
+
+	// filter the listing by policy
+	filtered, _, err := acl.TokenListFiltered(ACLTokenFilterOptions{Policy: policies[1].ID}, nil)
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, created2.AccessorID, filtered[0].AccessorID)
+
+	// and by role
+	role, _, err := acl.RoleCreate(&ACLRole{
+		Name: "list-role",
+		Policies: []*ACLRolePolicyLink{
+			&ACLRolePolicyLink{ID: policies[0].ID},
+		},
+	}, nil)
+	require.NoError(t, err)
+	created4, _, err := acl.TokenCreate(&ACLToken{
+		Description: "token created4",
+		Roles: []*ACLTokenRoleLink{
+			&ACLTokenRoleLink{ID: role.ID},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	filtered, _, err = acl.TokenListFiltered(ACLTokenFilterOptions{Role: role.ID}, nil)
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, created4.AccessorID, filtered[0].AccessorID)
 }
 
 func TestAPI_ACLToken_Clone(t *testing.T) {
@@ -621,6 +835,40 @@ func TestAPI_ACLToken_Clone(t *testing.T) {
 	require.Equal(t, cloned, read)
 }
 
+func TestAPI_ACLAuthorize(t *testing.T) {
+	t.Parallel()
+	c, s := makeACLClient(t)
+	defer s.Stop()
+
+	acl := c.ACL()
+
+	policy, _, err := acl.PolicyCreate(&ACLPolicy{
+		Name:  "kv-reader",
+		Rules: `key_prefix "foo/" { policy = "read" }`,
+	}, nil)
+	require.NoError(t, err)
+
+	token, _, err := acl.TokenCreate(&ACLToken{
+		Policies: []*ACLTokenPolicyLink{&ACLTokenPolicyLink{ID: policy.ID}},
+	}, nil)
+	require.NoError(t, err)
+
+	authz, _, err := acl.Authorize(token.AccessorID, "key", "foo/bar", "read", nil)
+	require.NoError(t, err)
+	require.True(t, authz.Allow)
+	require.Equal(t, `key_prefix "foo/" { policy = "read" }`, authz.Rule)
+
+	authz, _, err = acl.Authorize(token.AccessorID, "key", "foo/bar", "write", nil)
+	require.NoError(t, err)
+	require.False(t, authz.Allow)
+
+	// Without an accessor ID the request token is checked
+	authz, _, err = acl.Authorize("", "operator", "", "write", nil)
+	require.NoError(t, err)
+	require.True(t, authz.Allow)
+	require.Equal(t, `operator = "write"`, authz.Rule)
+}
+
 func TestAPI_RulesTranslate_FromToken(t *testing.T) {
 	t.Parallel()
 	c, s := makeACLClient(t)
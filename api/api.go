@@ -134,6 +134,10 @@ type QueryOptions struct {
 	// be provided for filtering.
 	NodeMeta map[string]string
 
+	// Labels is used to filter ACL token and policy listings down to the
+	// objects carrying all of the given label key/value pairs.
+	Labels map[string]string
+
 	// RelayFactor is used in keyring operations to cause responses to be
 	// relayed back to the sender through N other random nodes. Must be
 	// a value from 0 to 5 (inclusive).
@@ -619,6 +623,11 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 			r.params.Add("node-meta", key+":"+value)
 		}
 	}
+	if len(q.Labels) > 0 {
+		for key, value := range q.Labels {
+			r.params.Add("label", key+":"+value)
+		}
+	}
 	if q.RelayFactor != 0 {
 		r.params.Set("relay-factor", strconv.Itoa(int(q.RelayFactor)))
 	}
@@ -2,19 +2,93 @@ package acl
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 	"github.com/mitchellh/cli"
 )
 
-func PrintToken(token *api.ACLToken, ui cli.Ui, showMeta bool) {
+// MaxDataSize is the largest value the ACL commands will load for a single
+// field from the command line, a file or stdin. It matches the largest value
+// Consul accepts for a KV entry.
+const MaxDataSize = 512 * 1024
+
+// timeNow is used to compute relative times and may be replaced in tests.
+var timeNow = time.Now
+
+// FormatTime renders t as an RFC3339 timestamp followed by how long ago (or
+// how far in the future) it is relative to now. The timestamp is rendered in
+// the local time zone unless utc is set. The zero time renders as "".
+func FormatTime(t time.Time, utc bool) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	if utc {
+		t = t.UTC()
+	} else {
+		t = t.Local()
+	}
+
+	d := t.Sub(timeNow())
+	if d <= 0 {
+		return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), FormatDuration(-d))
+	}
+	return fmt.Sprintf("%s (in %s)", t.Format(time.RFC3339), FormatDuration(d))
+}
+
+// FormatDuration renders a duration using its two most significant units,
+// for example "3d4h", "14m" or "42s".
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	d = d.Round(time.Second)
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	switch {
+	case days > 0:
+		if hours > 0 {
+			return fmt.Sprintf("%dd%dh", days, hours)
+		}
+		return fmt.Sprintf("%dd", days)
+	case hours > 0:
+		if minutes > 0 {
+			return fmt.Sprintf("%dh%dm", hours, minutes)
+		}
+		return fmt.Sprintf("%dh", hours)
+	case minutes > 0:
+		if seconds > 0 {
+			return fmt.Sprintf("%dm%ds", minutes, seconds)
+		}
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+func PrintToken(token *api.ACLToken, ui cli.Ui, showMeta bool, utc bool) {
 	ui.Info(fmt.Sprintf("AccessorID:   %s", token.AccessorID))
 	ui.Info(fmt.Sprintf("SecretID:     %s", token.SecretID))
 	ui.Info(fmt.Sprintf("Description:  %s", token.Description))
 	ui.Info(fmt.Sprintf("Local:        %t", token.Local))
-	ui.Info(fmt.Sprintf("Create Time:  %v", token.CreateTime))
+	ui.Info(fmt.Sprintf("Create Time:  %s", FormatTime(token.CreateTime, utc)))
+	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
+		ui.Info(fmt.Sprintf("Expires:      %s", FormatTime(*token.ExpirationTime, utc)))
+	}
+	if len(token.Labels) > 0 {
+		ui.Info(fmt.Sprintf("Labels:       %s", FormatLabels(token.Labels)))
+	}
 	if showMeta {
 		ui.Info(fmt.Sprintf("Hash:         %x", token.Hash))
 		ui.Info(fmt.Sprintf("Create Index: %d", token.CreateIndex))
@@ -24,18 +98,249 @@ func PrintToken(token *api.ACLToken, ui cli.Ui, showMeta bool) {
 	for _, policy := range token.Policies {
 		ui.Info(fmt.Sprintf("   %s - %s", policy.ID, policy.Name))
 	}
+	if len(token.Roles) > 0 {
+		ui.Info(fmt.Sprintf("Roles:"))
+		for _, role := range token.Roles {
+			ui.Info(fmt.Sprintf("   %s - %s", role.ID, role.Name))
+		}
+	}
+	printServiceIdentities(token.ServiceIdentities, "", ui)
 	if token.Rules != "" {
 		ui.Info(fmt.Sprintf("Rules:"))
 		ui.Info(token.Rules)
 	}
 }
 
-func PrintTokenListEntry(token *api.ACLTokenListEntry, ui cli.Ui, showMeta bool) {
+// PrintTokenGrants prints the tree of grants that make up the effective
+// permissions of a token: each linked policy along with the datacenters it
+// applies to and its rules, then each service identity, then each linked role
+// with its policies and service identities, followed by any legacy rules. The
+// roles and policies maps are keyed by ID and must hold every role linked to
+// the token and every policy linked to either.
+func PrintTokenGrants(token *api.ACLToken, roles map[string]*api.ACLRole, policies map[string]*api.ACLPolicy, ui cli.Ui) {
+	ui.Info(fmt.Sprintf("Effective Grants:"))
+	if len(token.Policies) == 0 && len(token.Roles) == 0 && len(token.ServiceIdentities) == 0 && token.Rules == "" {
+		ui.Info(fmt.Sprintf("   <none>"))
+		return
+	}
+
+	for _, link := range token.Policies {
+		printPolicyGrant(link.ID, link.Name, policies, "   ", ui)
+	}
+
+	for _, s := range token.ServiceIdentities {
+		printServiceIdentityGrant(s, "   ", ui)
+	}
+
+	for _, link := range token.Roles {
+		ui.Info(fmt.Sprintf("   Role %q (%s)", link.Name, link.ID))
+		role, ok := roles[link.ID]
+		if !ok || role == nil {
+			ui.Info(fmt.Sprintf("      <role not found>"))
+			continue
+		}
+		if len(role.Policies) == 0 && len(role.ServiceIdentities) == 0 {
+			ui.Info(fmt.Sprintf("      <no policies>"))
+		}
+		for _, policyLink := range role.Policies {
+			printPolicyGrant(policyLink.ID, policyLink.Name, policies, "      ", ui)
+		}
+		for _, s := range role.ServiceIdentities {
+			printServiceIdentityGrant(s, "      ", ui)
+		}
+	}
+
+	if token.Rules != "" {
+		ui.Info(fmt.Sprintf("   Legacy Rules:"))
+		ui.Info(indent(token.Rules, "      "))
+	}
+}
+
+func printPolicyGrant(id, name string, policies map[string]*api.ACLPolicy, prefix string, ui cli.Ui) {
+	ui.Info(fmt.Sprintf("%sPolicy %q (%s)", prefix, name, id))
+	policy, ok := policies[id]
+	if !ok || policy == nil {
+		ui.Info(fmt.Sprintf("%s   <policy not found>", prefix))
+		return
+	}
+
+	ui.Info(fmt.Sprintf("%s   Datacenters: %s", prefix, formatDatacenters(policy.Datacenters)))
+	ui.Info(fmt.Sprintf("%s   Rules:", prefix))
+	ui.Info(indent(policy.Rules, prefix+"      "))
+}
+
+func printServiceIdentityGrant(s *api.ACLServiceIdentity, prefix string, ui cli.Ui) {
+	ui.Info(fmt.Sprintf("%sService Identity %q", prefix, s.ServiceName))
+	ui.Info(fmt.Sprintf("%s   Datacenters: %s", prefix, formatDatacenters(s.Datacenters)))
+	ui.Info(fmt.Sprintf("%s   Rules:", prefix))
+	ui.Info(indent(serviceIdentityRules(s), prefix+"      "))
+}
+
+// serviceIdentityRules returns the rules of the policy the servers synthesize
+// for the service identity.
+func serviceIdentityRules(s *api.ACLServiceIdentity) string {
+	identity := structs.ACLServiceIdentity{
+		ServiceName: s.ServiceName,
+		Datacenters: s.Datacenters,
+	}
+	return strings.TrimSpace(identity.SyntheticPolicy().Rules)
+}
+
+// TokenGrants is the graph of grants that make up the effective permissions
+// of a token, in the same shape as printed by PrintTokenGrants.
+type TokenGrants struct {
+	Policies          []*PolicyGrant
+	ServiceIdentities []*ServiceIdentityGrant
+	Roles             []*RoleGrant
+	LegacyRules       string `json:",omitempty"`
+}
+
+// PolicyGrant is a policy linked to a token or role. NotFound is set when the
+// policy no longer exists.
+type PolicyGrant struct {
+	ID          string
+	Name        string
+	Datacenters []string `json:",omitempty"`
+	Rules       string   `json:",omitempty"`
+	NotFound    bool     `json:",omitempty"`
+}
+
+// ServiceIdentityGrant is a service identity of a token or role along with
+// the rules the servers synthesize for it.
+type ServiceIdentityGrant struct {
+	ServiceName string
+	Datacenters []string `json:",omitempty"`
+	Rules       string
+}
+
+// RoleGrant is a role linked to a token. NotFound is set when the role no
+// longer exists.
+type RoleGrant struct {
+	ID                string
+	Name              string
+	Policies          []*PolicyGrant
+	ServiceIdentities []*ServiceIdentityGrant
+	NotFound          bool `json:",omitempty"`
+}
+
+// NewTokenGrants builds the graph of grants of a token. The roles and policies
+// maps are the same as for PrintTokenGrants.
+func NewTokenGrants(token *api.ACLToken, roles map[string]*api.ACLRole, policies map[string]*api.ACLPolicy) *TokenGrants {
+	grants := &TokenGrants{
+		Policies:          []*PolicyGrant{},
+		ServiceIdentities: newServiceIdentityGrants(token.ServiceIdentities),
+		Roles:             []*RoleGrant{},
+		LegacyRules:       token.Rules,
+	}
+
+	for _, link := range token.Policies {
+		grants.Policies = append(grants.Policies, newPolicyGrant(link.ID, link.Name, policies))
+	}
+
+	for _, link := range token.Roles {
+		grant := &RoleGrant{
+			ID:                link.ID,
+			Name:              link.Name,
+			Policies:          []*PolicyGrant{},
+			ServiceIdentities: []*ServiceIdentityGrant{},
+		}
+		if role, ok := roles[link.ID]; ok && role != nil {
+			for _, policyLink := range role.Policies {
+				grant.Policies = append(grant.Policies, newPolicyGrant(policyLink.ID, policyLink.Name, policies))
+			}
+			grant.ServiceIdentities = newServiceIdentityGrants(role.ServiceIdentities)
+		} else {
+			grant.NotFound = true
+		}
+		grants.Roles = append(grants.Roles, grant)
+	}
+
+	return grants
+}
+
+func newPolicyGrant(id, name string, policies map[string]*api.ACLPolicy) *PolicyGrant {
+	grant := &PolicyGrant{ID: id, Name: name}
+	policy, ok := policies[id]
+	if !ok || policy == nil {
+		grant.NotFound = true
+		return grant
+	}
+	grant.Datacenters = policy.Datacenters
+	grant.Rules = policy.Rules
+	return grant
+}
+
+func newServiceIdentityGrants(identities []*api.ACLServiceIdentity) []*ServiceIdentityGrant {
+	grants := make([]*ServiceIdentityGrant, 0, len(identities))
+	for _, s := range identities {
+		grants = append(grants, &ServiceIdentityGrant{
+			ServiceName: s.ServiceName,
+			Datacenters: s.Datacenters,
+			Rules:       serviceIdentityRules(s),
+		})
+	}
+	return grants
+}
+
+// FormatLabels renders a set of labels as a comma separated list of
+// key=value pairs sorted by key.
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// MergeLabels returns a copy of existing with updates applied on top. An
+// update with an empty value removes that label.
+func MergeLabels(existing, updates map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(updates))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range updates {
+		if v == "" {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// indent prefixes every non-empty line of s with prefix.
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func PrintTokenListEntry(token *api.ACLTokenListEntry, ui cli.Ui, showMeta bool, utc bool) {
 	ui.Info(fmt.Sprintf("AccessorID:   %s", token.AccessorID))
 	ui.Info(fmt.Sprintf("Description:  %s", token.Description))
 	ui.Info(fmt.Sprintf("Local:        %t", token.Local))
-	ui.Info(fmt.Sprintf("Create Time:  %v", token.CreateTime))
+	ui.Info(fmt.Sprintf("Create Time:  %s", FormatTime(token.CreateTime, utc)))
+	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
+		ui.Info(fmt.Sprintf("Expires:      %s", FormatTime(*token.ExpirationTime, utc)))
+	}
 	ui.Info(fmt.Sprintf("Legacy:       %t", token.Legacy))
+	if len(token.Labels) > 0 {
+		ui.Info(fmt.Sprintf("Labels:       %s", FormatLabels(token.Labels)))
+	}
 	if showMeta {
 		ui.Info(fmt.Sprintf("Hash:         %x", token.Hash))
 		ui.Info(fmt.Sprintf("Create Index: %d", token.CreateIndex))
@@ -45,6 +350,13 @@ func PrintTokenListEntry(token *api.ACLTokenListEntry, ui cli.Ui, showMeta bool)
 	for _, policy := range token.Policies {
 		ui.Info(fmt.Sprintf("   %s - %s", policy.ID, policy.Name))
 	}
+	if len(token.Roles) > 0 {
+		ui.Info(fmt.Sprintf("Roles:"))
+		for _, role := range token.Roles {
+			ui.Info(fmt.Sprintf("   %s - %s", role.ID, role.Name))
+		}
+	}
+	printServiceIdentities(token.ServiceIdentities, "", ui)
 }
 
 func PrintPolicy(policy *api.ACLPolicy, ui cli.Ui, showMeta bool) {
@@ -52,6 +364,9 @@ func PrintPolicy(policy *api.ACLPolicy, ui cli.Ui, showMeta bool) {
 	ui.Info(fmt.Sprintf("Name:         %s", policy.Name))
 	ui.Info(fmt.Sprintf("Description:  %s", policy.Description))
 	ui.Info(fmt.Sprintf("Datacenters:  %s", strings.Join(policy.Datacenters, ", ")))
+	if len(policy.Labels) > 0 {
+		ui.Info(fmt.Sprintf("Labels:       %s", FormatLabels(policy.Labels)))
+	}
 	if showMeta {
 		ui.Info(fmt.Sprintf("Hash:         %x", policy.Hash))
 		ui.Info(fmt.Sprintf("Create Index: %d", policy.CreateIndex))
@@ -61,11 +376,27 @@ func PrintPolicy(policy *api.ACLPolicy, ui cli.Ui, showMeta bool) {
 	ui.Info(policy.Rules)
 }
 
+// PrintPolicyUsage prints the tokens and roles that link to a policy, following
+// the output of PrintPolicy.
+func PrintPolicyUsage(usage *api.ACLPolicyUsage, ui cli.Ui) {
+	ui.Info(fmt.Sprintf("Used By Tokens:"))
+	for _, token := range usage.Tokens {
+		ui.Info(fmt.Sprintf("   %s - %s", token.AccessorID, token.Description))
+	}
+	ui.Info(fmt.Sprintf("Used By Roles:"))
+	for _, role := range usage.Roles {
+		ui.Info(fmt.Sprintf("   %s - %s", role.ID, role.Name))
+	}
+}
+
 func PrintPolicyListEntry(policy *api.ACLPolicyListEntry, ui cli.Ui, showMeta bool) {
 	ui.Info(fmt.Sprintf("%s:", policy.Name))
 	ui.Info(fmt.Sprintf("   ID:           %s", policy.ID))
 	ui.Info(fmt.Sprintf("   Description:  %s", policy.Description))
 	ui.Info(fmt.Sprintf("   Datacenters:  %s", strings.Join(policy.Datacenters, ", ")))
+	if len(policy.Labels) > 0 {
+		ui.Info(fmt.Sprintf("   Labels:       %s", FormatLabels(policy.Labels)))
+	}
 	if showMeta {
 		ui.Info(fmt.Sprintf("   Hash:         %x", policy.Hash))
 		ui.Info(fmt.Sprintf("   Create Index: %d", policy.CreateIndex))
@@ -73,6 +404,61 @@ func PrintPolicyListEntry(policy *api.ACLPolicyListEntry, ui cli.Ui, showMeta bo
 	}
 }
 
+func PrintRole(role *api.ACLRole, ui cli.Ui, showMeta bool) {
+	ui.Info(fmt.Sprintf("ID:           %s", role.ID))
+	ui.Info(fmt.Sprintf("Name:         %s", role.Name))
+	ui.Info(fmt.Sprintf("Description:  %s", role.Description))
+	if len(role.Labels) > 0 {
+		ui.Info(fmt.Sprintf("Labels:       %s", FormatLabels(role.Labels)))
+	}
+	if showMeta {
+		ui.Info(fmt.Sprintf("Hash:         %x", role.Hash))
+		ui.Info(fmt.Sprintf("Create Index: %d", role.CreateIndex))
+		ui.Info(fmt.Sprintf("Modify Index: %d", role.ModifyIndex))
+	}
+	ui.Info(fmt.Sprintf("Policies:"))
+	for _, policy := range role.Policies {
+		ui.Info(fmt.Sprintf("   %s - %s", policy.ID, policy.Name))
+	}
+	printServiceIdentities(role.ServiceIdentities, "", ui)
+}
+
+func PrintRoleListEntry(role *api.ACLRole, ui cli.Ui, showMeta bool) {
+	ui.Info(fmt.Sprintf("%s:", role.Name))
+	ui.Info(fmt.Sprintf("   ID:           %s", role.ID))
+	ui.Info(fmt.Sprintf("   Description:  %s", role.Description))
+	if len(role.Labels) > 0 {
+		ui.Info(fmt.Sprintf("   Labels:       %s", FormatLabels(role.Labels)))
+	}
+	if showMeta {
+		ui.Info(fmt.Sprintf("   Hash:         %x", role.Hash))
+		ui.Info(fmt.Sprintf("   Create Index: %d", role.CreateIndex))
+		ui.Info(fmt.Sprintf("   Modify Index: %d", role.ModifyIndex))
+	}
+	ui.Info(fmt.Sprintf("   Policies:"))
+	for _, policy := range role.Policies {
+		ui.Info(fmt.Sprintf("      %s - %s", policy.ID, policy.Name))
+	}
+	printServiceIdentities(role.ServiceIdentities, "   ", ui)
+}
+
+func printServiceIdentities(identities []*api.ACLServiceIdentity, prefix string, ui cli.Ui) {
+	if len(identities) == 0 {
+		return
+	}
+	ui.Info(fmt.Sprintf("%sService Identities:", prefix))
+	for _, s := range identities {
+		ui.Info(fmt.Sprintf("%s   %s (Datacenters: %s)", prefix, s.ServiceName, formatDatacenters(s.Datacenters)))
+	}
+}
+
+func formatDatacenters(datacenters []string) string {
+	if len(datacenters) == 0 {
+		return "all"
+	}
+	return strings.Join(datacenters, ", ")
+}
+
 func GetTokenIDFromPartial(client *api.Client, partialID string) (string, error) {
 	if partialID == "anonymous" {
 		return structs.ACLTokenAnonymousID, nil
@@ -155,6 +541,96 @@ func GetPolicyIDByName(client *api.Client, name string) (string, error) {
 	return "", fmt.Errorf("No such policy with name %s", name)
 }
 
+func GetRoleIDFromPartial(client *api.Client, partialID string) (string, error) {
+	// the full UUID string was given
+	if len(partialID) == 36 {
+		return partialID, nil
+	}
+
+	roles, _, err := client.ACL().RoleList(nil)
+	if err != nil {
+		return "", err
+	}
+
+	roleID := ""
+	for _, role := range roles {
+		if strings.HasPrefix(role.ID, partialID) {
+			if roleID != "" {
+				return "", fmt.Errorf("Partial role ID is not unique")
+			}
+			roleID = role.ID
+		}
+	}
+
+	if roleID == "" {
+		return "", fmt.Errorf("No such role ID with prefix: %s", partialID)
+	}
+
+	return roleID, nil
+}
+
+func GetRoleIDByName(client *api.Client, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("No name specified")
+	}
+
+	role, _, err := client.ACL().RoleReadByName(name, nil)
+	if err != nil {
+		return "", fmt.Errorf("No such role with name %s: %v", name, err)
+	}
+
+	return role.ID, nil
+}
+
+// ExtractServiceIdentities parses the values of -service-identity flags. Each
+// value is a service name optionally followed by a colon and a comma
+// separated list of datacenters, for example "web:dc1,dc2".
+func ExtractServiceIdentities(serviceIdents []string) ([]*api.ACLServiceIdentity, error) {
+	var out []*api.ACLServiceIdentity
+	for _, svcidRaw := range serviceIdents {
+		parts := strings.Split(svcidRaw, ":")
+		switch len(parts) {
+		case 2:
+			var datacenters []string
+			for _, dc := range strings.Split(parts[1], ",") {
+				if dc = strings.TrimSpace(dc); dc != "" {
+					datacenters = append(datacenters, dc)
+				}
+			}
+			out = append(out, &api.ACLServiceIdentity{
+				ServiceName: parts[0],
+				Datacenters: datacenters,
+			})
+		case 1:
+			out = append(out, &api.ACLServiceIdentity{
+				ServiceName: parts[0],
+			})
+		default:
+			return nil, fmt.Errorf("Malformed -service-identity argument: %q", svcidRaw)
+		}
+		if parts[0] == "" {
+			return nil, fmt.Errorf("Malformed -service-identity argument: %q", svcidRaw)
+		}
+	}
+	return out, nil
+}
+
+// MergeServiceIdentities adds the updates to the existing service identities.
+// An update for a service that already has an identity replaces it.
+func MergeServiceIdentities(existing, updates []*api.ACLServiceIdentity) []*api.ACLServiceIdentity {
+	out := make([]*api.ACLServiceIdentity, 0, len(existing)+len(updates))
+	replaced := make(map[string]struct{})
+	for _, s := range updates {
+		replaced[s.ServiceName] = struct{}{}
+	}
+	for _, s := range existing {
+		if _, ok := replaced[s.ServiceName]; !ok {
+			out = append(out, s)
+		}
+	}
+	return append(out, updates...)
+}
+
 func GetRulesFromLegacyToken(client *api.Client, tokenID string, isSecret bool) (string, error) {
 	tokenID, err := GetTokenIDFromPartial(client, tokenID)
 	if err != nil {
@@ -0,0 +1,120 @@
+package acl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in  time.Duration
+		out string
+	}{
+		{0, "0s"},
+		{42 * time.Second, "42s"},
+		{1500 * time.Millisecond, "2s"},
+		{14 * time.Minute, "14m"},
+		{14*time.Minute + 3*time.Second, "14m3s"},
+		{3 * time.Hour, "3h"},
+		{3*time.Hour + 12*time.Minute + 40*time.Second, "3h12m"},
+		{24 * time.Hour, "1d"},
+		{76 * time.Hour, "3d4h"},
+		{-5 * time.Minute, "5m"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.out, FormatDuration(tc.in), "duration %v", tc.in)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	now := time.Date(2019, 3, 21, 14, 2, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	require.Equal(t, "", FormatTime(time.Time{}, true))
+	require.Equal(t, "2019-03-21T13:50:00Z (12m ago)",
+		FormatTime(now.Add(-12*time.Minute), true))
+	require.Equal(t, "2019-03-21T14:16:00Z (in 14m)",
+		FormatTime(now.Add(14*time.Minute), true))
+
+	// Non-UTC rendering keeps the same instant.
+	local := FormatTime(now, false)
+	parsed, err := time.Parse(time.RFC3339, local[:len(local)-len(" (0s ago)")])
+	require.NoError(t, err)
+	require.True(t, parsed.Equal(now))
+}
+
+func TestExtractServiceIdentities(t *testing.T) {
+	t.Parallel()
+
+	out, err := ExtractServiceIdentities([]string{"web", "db:dc1", "api:dc1, dc2"})
+	require.NoError(t, err)
+	require.Equal(t, []*api.ACLServiceIdentity{
+		&api.ACLServiceIdentity{ServiceName: "web"},
+		&api.ACLServiceIdentity{ServiceName: "db", Datacenters: []string{"dc1"}},
+		&api.ACLServiceIdentity{ServiceName: "api", Datacenters: []string{"dc1", "dc2"}},
+	}, out)
+
+	for _, bad := range []string{"", ":dc1", "web:dc1:dc2"} {
+		_, err := ExtractServiceIdentities([]string{bad})
+		require.Error(t, err, "value %q", bad)
+	}
+}
+
+func TestMergeServiceIdentities(t *testing.T) {
+	t.Parallel()
+
+	existing := []*api.ACLServiceIdentity{
+		&api.ACLServiceIdentity{ServiceName: "web"},
+		&api.ACLServiceIdentity{ServiceName: "db"},
+	}
+	updates := []*api.ACLServiceIdentity{
+		&api.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+		&api.ACLServiceIdentity{ServiceName: "api"},
+	}
+
+	require.Equal(t, []*api.ACLServiceIdentity{
+		&api.ACLServiceIdentity{ServiceName: "db"},
+		&api.ACLServiceIdentity{ServiceName: "web", Datacenters: []string{"dc1"}},
+		&api.ACLServiceIdentity{ServiceName: "api"},
+	}, MergeServiceIdentities(existing, updates))
+}
+
+func TestNewTokenGrants(t *testing.T) {
+	t.Parallel()
+
+	token := &api.ACLToken{
+		Policies:          []*api.ACLTokenPolicyLink{{ID: "p1", Name: "kv-read"}},
+		ServiceIdentities: []*api.ACLServiceIdentity{{ServiceName: "web", Datacenters: []string{"dc1"}}},
+		Roles: []*api.ACLTokenRoleLink{
+			{ID: "r1", Name: "ops"},
+			{ID: "r2", Name: "deleted"},
+		},
+	}
+	roles := map[string]*api.ACLRole{
+		"r1": {ID: "r1", Name: "ops", Policies: []*api.ACLRolePolicyLink{{ID: "p2", Name: "gone"}}},
+	}
+	policies := map[string]*api.ACLPolicy{
+		"p1": {ID: "p1", Name: "kv-read", Rules: `key_prefix "" { policy = "read" }`},
+	}
+
+	grants := NewTokenGrants(token, roles, policies)
+	require.Equal(t, []*PolicyGrant{
+		{ID: "p1", Name: "kv-read", Rules: `key_prefix "" { policy = "read" }`},
+	}, grants.Policies)
+	require.Len(t, grants.ServiceIdentities, 1)
+	require.Equal(t, "web", grants.ServiceIdentities[0].ServiceName)
+	require.Equal(t, []string{"dc1"}, grants.ServiceIdentities[0].Datacenters)
+	require.Contains(t, grants.ServiceIdentities[0].Rules, `service "web-sidecar-proxy" {`)
+	require.Len(t, grants.Roles, 2)
+	require.Equal(t, []*PolicyGrant{{ID: "p2", Name: "gone", NotFound: true}}, grants.Roles[0].Policies)
+	require.Empty(t, grants.Roles[0].ServiceIdentities)
+	require.False(t, grants.Roles[0].NotFound)
+	require.True(t, grants.Roles[1].NotFound)
+}
@@ -1,11 +1,18 @@
 package bootstrap
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
 	"github.com/mitchellh/cli"
 )
 
@@ -20,10 +27,44 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	manifest   string
+	resetIndex uint64
+	dataDir    string
+	utc        bool
+
+	testStdin io.Reader
 }
 
+// These name files of the server in agent/consul, which is not imported to
+// keep the CLI small. TestBootstrapCommand_reset fails if they drift apart,
+// as the reset only works when the server reads the file written here.
+const (
+	// aclBootstrapReset is the file in the data dir of the leader holding the
+	// reset index that allows bootstrapping once more.
+	aclBootstrapReset = "acl-bootstrap-reset"
+
+	// raftState is the directory servers keep their Raft data in.
+	raftState = "raft/"
+)
+
+// resetIndexRe extracts the reset index from the errors returned when
+// bootstrapping is no longer allowed.
+var resetIndexRe = regexp.MustCompile(`reset index: (\d+)`)
+
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.manifest, "manifest", "", "A JSON manifest of policies, "+
+		"roles and tokens to create with the new bootstrap token. May be prefixed with '@' to "+
+		"indicate that the value is a file path to load the manifest from. '-' may "+
+		"also be given to indicate that the manifest is available on stdin")
+	c.flags.Uint64Var(&c.resetIndex, "reset-index", 0, "The reset index reported when "+
+		"bootstrapping is no longer allowed. It is written to the bootstrap reset "+
+		"file in -data-dir before bootstrapping again. This must be run on the "+
+		"current leader as the user the server runs as")
+	c.flags.StringVar(&c.dataDir, "data-dir", "", "The data directory of the "+
+		"server to write the bootstrap reset file to. Required with -reset-index")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of the local time zone")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -35,22 +76,94 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if (c.resetIndex == 0) != (c.dataDir == "") {
+		c.UI.Error("The -reset-index and -data-dir options must be given together")
+		return 1
+	}
+
+	// The manifest is parsed up front so a bad manifest does not use up the
+	// one-time bootstrap.
+	var m *manifest
+	if c.manifest != "" {
+		raw, err := helpers.LoadDataSourceMax(c.manifest, c.testStdin, acl.MaxDataSize)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error loading manifest: %v", err))
+			return 1
+		}
+		m, err = parseManifest(raw)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid manifest: %v", err))
+			return 1
+		}
+	}
+
 	client, err := c.http.APIClient()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
 		return 1
 	}
 
+	if c.resetIndex != 0 {
+		if err := writeResetFile(c.dataDir, c.resetIndex); err != nil {
+			c.UI.Error(fmt.Sprintf("Error writing the bootstrap reset file: %v", err))
+			return 1
+		}
+	}
+
 	token, _, err := client.ACL().Bootstrap()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed ACL bootstrapping: %v", err))
+		if m := resetIndexRe.FindStringSubmatch(err.Error()); m != nil {
+			c.UI.Error(fmt.Sprintf("To bootstrap again, run this command on the leader "+
+				"with -reset-index=%s and -data-dir set to its data directory", m[1]))
+		}
+		return 1
+	}
+
+	if m == nil {
+		acl.PrintToken(token, c.UI, false, c.utc)
+		return 0
+	}
+
+	result, err := applyManifest(client, token.SecretID, m)
+	if err != nil {
+		// The bootstrap token can't be taken back, so make sure it isn't lost.
+		acl.PrintToken(token, c.UI, false, c.utc)
+		c.UI.Error(fmt.Sprintf("Failed to apply manifest: %v", err))
 		return 1
 	}
 
-	acl.PrintToken(token, c.UI, false)
+	result.BootstrapToken = manifestResultToken{
+		AccessorID:  token.AccessorID,
+		SecretID:    token.SecretID,
+		Description: token.Description,
+	}
+	out, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to encode the manifest result: %v", err))
+		return 1
+	}
+	c.UI.Output(string(out))
 	return 0
 }
 
+// writeResetFile writes the reset index into the bootstrap reset file of a
+// server so that the ACL system can be bootstrapped once more. This is how
+// operators recover when all tokens capable of ACL management were lost. The
+// file is only read by the leader, so dataDir needs to be the data directory
+// of the current leader. The file is only readable by its owner, so this has
+// to run as the user the server runs as.
+func writeResetFile(dataDir string, resetIndex uint64) error {
+	// Catch typos in the path rather than silently creating a file that
+	// will never be read.
+	if _, err := os.Stat(filepath.Join(dataDir, raftState)); err != nil {
+		return fmt.Errorf("%q does not look like the data directory of a Consul server: %v", dataDir, err)
+	}
+
+	path := filepath.Join(dataDir, aclBootstrapReset)
+	return ioutil.WriteFile(path, []byte(fmt.Sprintf("%d", resetIndex)), 0600)
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -61,12 +174,20 @@ func (c *cmd) Help() string {
 
 const synopsis = "Bootstrap Consul's ACL system"
 
-// TODO (ACL-V2) - maybe embed link to bootstrap reset docs
 const help = `
 Usage: consul acl bootstrap [options]
 
   The bootstrap command will request Consul to generate a new token with unlimited privileges to use
   for management purposes and output its details. This can only be done once and afterwards bootstrapping
-  will be disabled. If all tokens are lost and you need to bootstrap again you can follow the bootstrap
-  reset procedure
+  will be disabled. If all tokens are lost you can bootstrap again by running this command on the
+  leader with the reset index reported by the failed bootstrap and the data directory of the leader.
+  Run it as the user the server runs as, so that the server can read the reset file:
+
+      $ sudo -u consul consul acl bootstrap -reset-index=13 -data-dir=/opt/consul/data
+
+  A manifest of policies, roles and tokens may be given to create them with the new token right away. The IDs
+  of every created object, including the bootstrap token, are then output as JSON. If any object
+  fails to be created the ones created before it are removed again:
+
+      $ consul acl bootstrap -manifest=@acl-manifest.json
 `
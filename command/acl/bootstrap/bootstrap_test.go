@@ -1,17 +1,20 @@
 package bootstrap
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/logger"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBootstrapCommand_noTabs(t *testing.T) {
@@ -45,6 +48,7 @@ func TestBootstrapCommand(t *testing.T) {
 
 	args := []string{
 		"-http-addr=" + a.HTTPAddr(),
+		"-utc",
 	}
 
 	code := cmd.Run(args)
@@ -53,4 +57,231 @@ func TestBootstrapCommand(t *testing.T) {
 	output := ui.OutputWriter.String()
 	assert.Contains(output, "Bootstrap Token")
 	assert.Contains(output, structs.ACLPolicyGlobalManagementID)
+	assert.Regexp(`Create Time:  \S+Z \(`, output)
+}
+
+func TestBootstrapCommand_manifest(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// An invalid manifest must not use up the bootstrap.
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		`-manifest={"BindingRules": []}`,
+	})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Invalid manifest")
+
+	ui = cli.NewMockUi()
+	cmd := New(ui)
+	cmd.testStdin = strings.NewReader(`{
+		"Policies": [
+			{"Name": "agents", "Rules": "node_prefix \"\" { policy = \"write\" }"},
+			{"Name": "readers", "Rules": "key_prefix \"\" { policy = \"read\" }"}
+		],
+		"Roles": [
+			{"Name": "web", "Policies": ["readers"], "ServiceIdentities": [{"ServiceName": "web"}]}
+		],
+		"Tokens": [
+			{"Description": "agent token", "Policies": ["agents"]},
+			{"Description": "web token", "Roles": ["web"]}
+		]
+	}`)
+	code = cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-manifest=-",
+	})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	var result manifestResult
+	require.NoError(json.Unmarshal(ui.OutputWriter.Bytes(), &result))
+	require.Len(result.BootstrapToken.SecretID, 36)
+	require.Len(result.Policies, 2)
+	require.Equal("agents", result.Policies[0].Name)
+	require.Equal("readers", result.Policies[1].Name)
+	require.Len(result.Roles, 1)
+	require.Equal("web", result.Roles[0].Name)
+	require.Len(result.Tokens, 2)
+
+	client := a.Client()
+	token, _, err := client.ACL().TokenRead(result.Tokens[0].AccessorID,
+		&api.QueryOptions{Token: result.BootstrapToken.SecretID})
+	require.NoError(err)
+	require.Equal("agent token", token.Description)
+	require.Len(token.Policies, 1)
+	require.Equal(result.Policies[0].ID, token.Policies[0].ID)
+
+	role, _, err := client.ACL().RoleRead(result.Roles[0].ID,
+		&api.QueryOptions{Token: result.BootstrapToken.SecretID})
+	require.NoError(err)
+	require.Len(role.Policies, 1)
+	require.Equal(result.Policies[1].ID, role.Policies[0].ID)
+	require.Len(role.ServiceIdentities, 1)
+
+	token, _, err = client.ACL().TokenRead(result.Tokens[1].AccessorID,
+		&api.QueryOptions{Token: result.BootstrapToken.SecretID})
+	require.NoError(err)
+	require.Len(token.Roles, 1)
+	require.Equal(result.Roles[0].ID, token.Roles[0].ID)
+}
+
+func TestBootstrapCommand_manifestRollback(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		`-manifest={
+			"Policies": [{"Name": "agents"}],
+			"Roles": [{"Name": "agents", "Policies": ["agents"]}],
+			"Tokens": [{"Description": "broken", "Policies": ["missing"]}]
+		}`,
+	})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Failed to apply manifest")
+
+	// The bootstrap token is still printed so it isn't lost.
+	output := ui.OutputWriter.String()
+	require.Contains(output, "Bootstrap Token")
+
+	client := a.Client()
+	policies, _, err := client.ACL().PolicyList(&api.QueryOptions{Token: secretFromOutput(t, output)})
+	require.NoError(err)
+	for _, policy := range policies {
+		require.NotEqual("agents", policy.Name)
+	}
+	roles, _, err := client.ACL().RoleList(&api.QueryOptions{Token: secretFromOutput(t, output)})
+	require.NoError(err)
+	require.Empty(roles)
+}
+
+func TestBootstrapCommand_reset(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr()})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	oldSecret := secretFromOutput(t, ui.OutputWriter.String())
+
+	// A second bootstrap is refused and explains how to reset.
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{"-http-addr=" + a.HTTPAddr()})
+	require.Equal(1, code)
+	m := resetIndexRe.FindStringSubmatch(ui.ErrorWriter.String())
+	require.NotNil(m, ui.ErrorWriter.String())
+	require.Contains(ui.ErrorWriter.String(), "-reset-index="+m[1])
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-reset-index=" + m[1],
+	})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "must be given together")
+
+	notServerDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(notServerDir)
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-reset-index=" + m[1],
+		"-data-dir=" + notServerDir,
+	})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "does not look like the data directory")
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-reset-index=" + m[1],
+		"-data-dir=" + a.Config.DataDir,
+	})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	newSecret := secretFromOutput(t, ui.OutputWriter.String())
+	require.NotEqual(oldSecret, newSecret)
+}
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		raw  string
+		err  string
+	}{
+		{"empty", `{}`, ""},
+		{"unknown field", `{"BindingRules": []}`, "unknown field"},
+		{"missing policy name", `{"Policies": [{"Rules": ""}]}`, "missing a Name"},
+		{"duplicate policy", `{"Policies": [{"Name": "a"}, {"Name": "a"}]}`, "more than once"},
+		{"missing role name", `{"Roles": [{"Policies": ["a"]}]}`, "missing a Name"},
+		{"duplicate role", `{"Roles": [{"Name": "a", "Policies": ["a"]}, {"Name": "a", "Policies": ["a"]}]}`, "more than once"},
+		{"role without policies", `{"Roles": [{"Name": "a"}]}`, "at least one policy or service identity"},
+		{"token without policies", `{"Tokens": [{"Description": "x"}]}`, "at least one policy or role"},
+		{"token with a role", `{"Tokens": [{"Roles": ["a"]}]}`, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseManifest(tc.raw)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func secretFromOutput(t *testing.T, output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "SecretID:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "SecretID:"))
+		}
+	}
+	t.Fatalf("no SecretID in output: %s", output)
+	return ""
 }
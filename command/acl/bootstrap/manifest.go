@@ -0,0 +1,209 @@
+package bootstrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// manifest describes the ACL objects to create right after bootstrapping.
+// Policies are created first, then roles and then tokens, so that roles and
+// tokens may link the objects created before them by name.
+type manifest struct {
+	Policies []*manifestPolicy
+	Roles    []*manifestRole
+	Tokens   []*manifestToken
+}
+
+type manifestPolicy struct {
+	Name        string
+	Description string
+	Rules       string
+	Datacenters []string
+	Labels      map[string]string
+}
+
+type manifestRole struct {
+	Name              string
+	Description       string
+	Policies          []string
+	ServiceIdentities []*api.ACLServiceIdentity
+	Labels            map[string]string
+}
+
+type manifestToken struct {
+	Description string
+	Local       bool
+	Policies    []string
+	Roles       []string
+	Labels      map[string]string
+}
+
+// manifestResult is emitted as JSON once the manifest has been applied.
+type manifestResult struct {
+	BootstrapToken manifestResultToken
+	Policies       []manifestResultPolicy
+	Roles          []manifestResultRole
+	Tokens         []manifestResultToken
+}
+
+type manifestResultPolicy struct {
+	ID   string
+	Name string
+}
+
+type manifestResultRole struct {
+	ID   string
+	Name string
+}
+
+type manifestResultToken struct {
+	AccessorID  string
+	SecretID    string
+	Description string
+}
+
+// parseManifest decodes and sanity checks a manifest. Unknown fields are
+// rejected so that a typo does not silently leave objects uncreated.
+func parseManifest(raw string) (*manifest, error) {
+	dec := json.NewDecoder(bytes.NewBufferString(raw))
+	dec.DisallowUnknownFields()
+
+	var m manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{})
+	for i, p := range m.Policies {
+		if p == nil || p.Name == "" {
+			return nil, fmt.Errorf("Policy %d is missing a Name", i)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("Policy %q is listed more than once", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+
+	names = make(map[string]struct{})
+	for i, r := range m.Roles {
+		if r == nil || r.Name == "" {
+			return nil, fmt.Errorf("Role %d is missing a Name", i)
+		}
+		if _, ok := names[r.Name]; ok {
+			return nil, fmt.Errorf("Role %q is listed more than once", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		if len(r.Policies) == 0 && len(r.ServiceIdentities) == 0 {
+			return nil, fmt.Errorf("Role %q must link at least one policy or service identity", r.Name)
+		}
+	}
+
+	for i, t := range m.Tokens {
+		if t == nil || len(t.Policies) == 0 && len(t.Roles) == 0 {
+			return nil, fmt.Errorf("Token %d must link at least one policy or role", i)
+		}
+	}
+
+	return &m, nil
+}
+
+// applyManifest creates every object in the manifest using the given token.
+// If any object fails to be created, the objects created so far are deleted
+// again so that a failed run can simply be retried with a fixed manifest. The
+// returned error names any object that could not be deleted.
+func applyManifest(client *api.Client, token string, m *manifest) (*manifestResult, error) {
+	var result manifestResult
+	wopts := &api.WriteOptions{Token: token}
+
+	rollback := func(err error) error {
+		var failed []string
+		for _, t := range result.Tokens {
+			if _, delErr := client.ACL().TokenDelete(t.AccessorID, wopts); delErr != nil {
+				failed = append(failed, fmt.Sprintf("token %q (%v)", t.AccessorID, delErr))
+			}
+		}
+		for _, r := range result.Roles {
+			if _, delErr := client.ACL().RoleDelete(r.ID, wopts); delErr != nil {
+				failed = append(failed, fmt.Sprintf("role %q (%v)", r.ID, delErr))
+			}
+		}
+		for _, p := range result.Policies {
+			if _, delErr := client.ACL().PolicyDelete(p.ID, wopts); delErr != nil {
+				failed = append(failed, fmt.Sprintf("policy %q (%v)", p.ID, delErr))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%v. Failed to remove the objects created before the "+
+				"failure, they must be deleted manually: %s", err, strings.Join(failed, ", "))
+		}
+		return err
+	}
+
+	for _, p := range m.Policies {
+		policy, _, err := client.ACL().PolicyCreate(&api.ACLPolicy{
+			Name:        p.Name,
+			Description: p.Description,
+			Rules:       p.Rules,
+			Datacenters: p.Datacenters,
+			Labels:      p.Labels,
+		}, wopts)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("Failed to create policy %q: %v", p.Name, err))
+		}
+		result.Policies = append(result.Policies, manifestResultPolicy{
+			ID:   policy.ID,
+			Name: policy.Name,
+		})
+	}
+
+	for _, r := range m.Roles {
+		newRole := &api.ACLRole{
+			Name:              r.Name,
+			Description:       r.Description,
+			ServiceIdentities: r.ServiceIdentities,
+			Labels:            r.Labels,
+		}
+		for _, name := range r.Policies {
+			newRole.Policies = append(newRole.Policies, &api.ACLRolePolicyLink{Name: name})
+		}
+
+		role, _, err := client.ACL().RoleCreate(newRole, wopts)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("Failed to create role %q: %v", r.Name, err))
+		}
+		result.Roles = append(result.Roles, manifestResultRole{
+			ID:   role.ID,
+			Name: role.Name,
+		})
+	}
+
+	for i, t := range m.Tokens {
+		newToken := &api.ACLToken{
+			Description: t.Description,
+			Local:       t.Local,
+			Labels:      t.Labels,
+		}
+		for _, name := range t.Policies {
+			newToken.Policies = append(newToken.Policies, &api.ACLTokenPolicyLink{Name: name})
+		}
+		for _, name := range t.Roles {
+			newToken.Roles = append(newToken.Roles, &api.ACLTokenRoleLink{Name: name})
+		}
+
+		token, _, err := client.ACL().TokenCreate(newToken, wopts)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("Failed to create token %d: %v", i, err))
+		}
+		result.Tokens = append(result.Tokens, manifestResultToken{
+			AccessorID:  token.AccessorID,
+			SecretID:    token.SecretID,
+			Description: token.Description,
+		})
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,385 @@
+package browse
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	showMeta bool
+	utc      bool
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
+
+	client *api.Client
+	// kind and entries hold the most recent listing so that later
+	// commands can refer to its entries by number.
+	kind    string
+	entries []*entry
+}
+
+// entry is a single line of a listing.
+type entry struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that metadata such "+
+		"as the content hash and raft indices should be shown in detail views")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of "+
+		"the local time zone")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+	c.client = client
+
+	ask := c.prompter()
+	c.UI.Output(`Type "help" for a list of commands.`)
+	for {
+		line, err := ask("acl>")
+		if err != nil {
+			// End of input and interrupts both end the session.
+			return 0
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "tokens", "policies", "roles":
+			err = c.list(fields[0], strings.Join(fields[1:], " "))
+		case "show":
+			err = c.show(fields[1:])
+		case "delete":
+			err = c.delete(fields[1:], ask)
+		case "edit":
+			err = c.edit(fields[1:], ask)
+		case "help":
+			c.UI.Output(strings.TrimSpace(commandHelp))
+		case "quit", "exit":
+			return 0
+		default:
+			err = fmt.Errorf("Unknown command %q, type \"help\" for a list of commands", fields[0])
+		}
+
+		if err != nil {
+			c.UI.Error(err.Error())
+		}
+	}
+}
+
+// prompter returns the function used to read each line of input. Outside of
+// tests this asks through the UI so that prompts are written to the terminal.
+func (c *cmd) prompter() func(string) (string, error) {
+	if c.testStdin == nil {
+		return c.UI.Ask
+	}
+
+	scanner := bufio.NewScanner(c.testStdin)
+	return func(string) (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+// list fetches the given kind of ACL object and prints a numbered listing of
+// those whose ID, name or description contain the search string.
+func (c *cmd) list(kind, search string) error {
+	var all []*entry
+	switch kind {
+	case "tokens":
+		tokens, _, err := c.client.ACL().TokenList(nil)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve the token list: %v", err)
+		}
+		for _, token := range tokens {
+			all = append(all, &entry{ID: token.AccessorID, Description: token.Description})
+		}
+	case "policies":
+		policies, _, err := c.client.ACL().PolicyList(nil)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve the policy list: %v", err)
+		}
+		for _, policy := range policies {
+			all = append(all, &entry{ID: policy.ID, Name: policy.Name, Description: policy.Description})
+		}
+	case "roles":
+		roles, _, err := c.client.ACL().RoleList(nil)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve the role list: %v", err)
+		}
+		for _, role := range roles {
+			all = append(all, &entry{ID: role.ID, Name: role.Name, Description: role.Description})
+		}
+	}
+
+	search = strings.ToLower(search)
+	c.kind = kind
+	c.entries = nil
+	for _, e := range all {
+		if search == "" ||
+			strings.Contains(strings.ToLower(e.ID), search) ||
+			strings.Contains(strings.ToLower(e.Name), search) ||
+			strings.Contains(strings.ToLower(e.Description), search) {
+			c.entries = append(c.entries, e)
+		}
+	}
+
+	if len(c.entries) == 0 {
+		c.UI.Info(fmt.Sprintf("No %s found", kind))
+		return nil
+	}
+	for i, e := range c.entries {
+		label := e.Name
+		if label == "" {
+			label = e.Description
+		} else if e.Description != "" {
+			label = fmt.Sprintf("%s (%s)", e.Name, e.Description)
+		}
+		c.UI.Info(fmt.Sprintf("%4d  %s  %s", i+1, e.ID, label))
+	}
+	return nil
+}
+
+// selectEntry resolves the argument of a command to an entry of the most
+// recent listing, either by its number or by a unique ID prefix.
+func (c *cmd) selectEntry(args []string) (*entry, error) {
+	if len(args) != 1 {
+		return nil, errors.New("Expected exactly one entry number or ID")
+	}
+	if c.kind == "" {
+		return nil, errors.New("Nothing listed yet, run \"tokens\", \"policies\" or \"roles\" first")
+	}
+
+	if n, err := strconv.Atoi(args[0]); err == nil {
+		if n < 1 || n > len(c.entries) {
+			return nil, fmt.Errorf("No entry %d in the current listing", n)
+		}
+		return c.entries[n-1], nil
+	}
+
+	var found *entry
+	for _, e := range c.entries {
+		if strings.HasPrefix(e.ID, args[0]) {
+			if found != nil {
+				return nil, fmt.Errorf("ID prefix %q matches multiple entries", args[0])
+			}
+			found = e
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("No entry with ID prefix %q in the current listing", args[0])
+	}
+	return found, nil
+}
+
+func (c *cmd) show(args []string) error {
+	e, err := c.selectEntry(args)
+	if err != nil {
+		return err
+	}
+
+	switch c.kind {
+	case "tokens":
+		token, _, err := c.client.ACL().TokenRead(e.ID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading token %q: %v", e.ID, err)
+		}
+		acl.PrintToken(token, c.UI, c.showMeta, c.utc)
+	case "policies":
+		policy, _, err := c.client.ACL().PolicyRead(e.ID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading policy %q: %v", e.ID, err)
+		}
+		acl.PrintPolicy(policy, c.UI, c.showMeta)
+	case "roles":
+		role, _, err := c.client.ACL().RoleRead(e.ID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading role %q: %v", e.ID, err)
+		}
+		if role == nil {
+			return fmt.Errorf("Role not found with ID %q", e.ID)
+		}
+		acl.PrintRole(role, c.UI, c.showMeta)
+	}
+	return nil
+}
+
+// delete removes the selected entry. As a guard against deleting the wrong
+// object the full ID has to be typed back before anything is deleted.
+func (c *cmd) delete(args []string, ask func(string) (string, error)) error {
+	e, err := c.selectEntry(args)
+	if err != nil {
+		return err
+	}
+
+	confirm, err := ask(fmt.Sprintf("Type the full ID %s to confirm deletion:", e.ID))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(confirm) != e.ID {
+		c.UI.Info("Deletion aborted")
+		return nil
+	}
+
+	switch c.kind {
+	case "tokens":
+		_, err = c.client.ACL().TokenDelete(e.ID, nil)
+	case "policies":
+		_, err = c.client.ACL().PolicyDelete(e.ID, nil)
+	case "roles":
+		_, err = c.client.ACL().RoleDelete(e.ID, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting %q: %v", e.ID, err)
+	}
+
+	for i, other := range c.entries {
+		if other == e {
+			c.entries = append(c.entries[:i], c.entries[i+1:]...)
+			break
+		}
+	}
+	c.UI.Info(fmt.Sprintf("%q deleted successfully", e.ID))
+	return nil
+}
+
+// edit changes the description of the selected entry after asking for the
+// new value and a confirmation.
+func (c *cmd) edit(args []string, ask func(string) (string, error)) error {
+	e, err := c.selectEntry(args)
+	if err != nil {
+		return err
+	}
+
+	description, err := ask("New description:")
+	if err != nil {
+		return err
+	}
+	confirm, err := ask(fmt.Sprintf("Update the description of %s? (yes/no)", e.ID))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(confirm) != "yes" {
+		c.UI.Info("Update aborted")
+		return nil
+	}
+
+	switch c.kind {
+	case "tokens":
+		token, _, err := c.client.ACL().TokenRead(e.ID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading token %q: %v", e.ID, err)
+		}
+		token.Description = description
+		_, _, err = c.client.ACL().TokenUpdate(token, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to update token %q: %v", e.ID, err)
+		}
+	case "policies":
+		policy, _, err := c.client.ACL().PolicyRead(e.ID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading policy %q: %v", e.ID, err)
+		}
+		policy.Description = description
+		_, _, err = c.client.ACL().PolicyUpdate(policy, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to update policy %q: %v", e.ID, err)
+		}
+	case "roles":
+		role, _, err := c.client.ACL().RoleRead(e.ID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading role %q: %v", e.ID, err)
+		}
+		if role == nil {
+			return fmt.Errorf("Role not found with ID %q", e.ID)
+		}
+		role.Description = description
+		_, _, err = c.client.ACL().RoleUpdate(role, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to update role %q: %v", e.ID, err)
+		}
+	}
+
+	e.Description = description
+	c.UI.Info(fmt.Sprintf("%q updated successfully", e.ID))
+	return nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Interactively browse ACL tokens, policies and roles"
+const help = `
+Usage: consul acl browse [options]
+
+  Starts an interactive session for navigating ACL tokens, policies and
+  roles. Listings can be searched, entries inspected in detail, and
+  descriptions edited or entries deleted after a confirmation.
+
+  Type "help" within the session for a list of commands.
+`
+
+const commandHelp = `
+tokens [search]     List tokens, optionally only those matching search
+policies [search]   List policies, optionally only those matching search
+roles [search]      List roles, optionally only those matching search
+show <entry>        Show the details of an entry of the current listing
+edit <entry>        Change the description of an entry
+delete <entry>      Delete an entry, after typing back its full ID
+help                Show this help
+quit                End the session
+
+An entry is referenced by its number in the current listing or by a unique
+prefix of its ID.
+`
@@ -0,0 +1,93 @@
+package browse
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/logger"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestBrowseCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "browse-policy", Rules: `node_prefix "" { policy = "read" }`},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(err)
+	_, _, err = client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "other-policy", Rules: `node_prefix "" { policy = "read" }`},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	cmd.testStdin = strings.NewReader(strings.Join([]string{
+		"policies browse",
+		"show 1",
+		"edit 1",
+		"browsed description",
+		"yes",
+		"delete 1",
+		"not-the-id",
+		"delete 1",
+		policy.ID,
+		"bogus",
+		"quit",
+	}, "\n"))
+
+	code := cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+	})
+	require.Equal(0, code)
+
+	output := ui.OutputWriter.String()
+	require.Contains(output, fmt.Sprintf("   1  %s  browse-policy", policy.ID))
+	require.NotContains(output, "other-policy")
+	require.Contains(output, `node_prefix "" { policy = "read" }`)
+	require.Contains(output, "Deletion aborted")
+	require.Contains(output, fmt.Sprintf("%q deleted successfully", policy.ID))
+	require.Contains(ui.ErrorWriter.String(), `Unknown command "bogus"`)
+
+	require.Contains(output, fmt.Sprintf("%q updated successfully", policy.ID))
+
+	_, _, err = client.ACL().PolicyRead(policy.ID, &api.QueryOptions{Token: "root"})
+	require.Error(err)
+}
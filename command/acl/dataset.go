@@ -0,0 +1,19 @@
+package acl
+
+import (
+	"github.com/hashicorp/consul/api"
+)
+
+// Dataset holds the complete set of ACL objects of a datacenter. It is the
+// file format written by "consul acl export" and read by "consul acl import".
+type Dataset struct {
+	Policies []*api.ACLPolicy
+	Roles    []*api.ACLRole
+	Tokens   []*api.ACLToken
+}
+
+// MaxDatasetSize is the largest dataset "consul acl import" will load. A
+// dataset holds every object of a datacenter so it is allowed to be much
+// larger than MaxDataSize, but a bound still keeps a wrong path such as a
+// device file from being read forever.
+const MaxDatasetSize = 64 * 1024 * 1024
@@ -0,0 +1,157 @@
+package aclexport
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	includeSecrets bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.includeSecrets, "include-secrets", false, "Include the "+
+		"SecretIDs of the tokens so that they keep working after being imported "+
+		"elsewhere. Requires acl:write. Without this imported tokens get new "+
+		"SecretIDs")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	data, err := c.export(client)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	out, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to encode the ACL data: %v", err))
+		return 1
+	}
+	c.UI.Output(string(out))
+	return 0
+}
+
+// export reads every policy, role and token. The builtin objects exist in
+// every cluster and are left out.
+func (c *cmd) export(client *api.Client) (*acl.Dataset, error) {
+	data := &acl.Dataset{
+		Policies: []*api.ACLPolicy{},
+		Roles:    []*api.ACLRole{},
+		Tokens:   []*api.ACLToken{},
+	}
+
+	// The servers redact token secrets unless the request token has
+	// acl:write, so that is checked before anything is read.
+	if c.includeSecrets {
+		authz, _, err := client.ACL().Authorize("", "acl", "", "write", nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check the token privileges: %v", err)
+		}
+		if !authz.Allow {
+			return nil, fmt.Errorf("Exporting token secrets requires acl:write")
+		}
+	}
+
+	policies, _, err := client.ACL().PolicyList(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve the policy list: %v", err)
+	}
+	for _, stub := range policies {
+		if structs.ACLIDReserved(stub.ID) {
+			continue
+		}
+		policy, _, err := client.ACL().PolicyRead(stub.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading policy %q: %v", stub.ID, err)
+		}
+		data.Policies = append(data.Policies, policy)
+	}
+
+	roles, _, err := client.ACL().RoleList(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve the role list: %v", err)
+	}
+	data.Roles = append(data.Roles, roles...)
+
+	tokens, _, err := client.ACL().TokenList(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve the token list: %v", err)
+	}
+	for _, stub := range tokens {
+		if structs.ACLIDReserved(stub.AccessorID) {
+			continue
+		}
+		if stub.Legacy {
+			c.UI.Warn(fmt.Sprintf("Skipping legacy token %q, it has to be upgraded "+
+				"before it can be exported", stub.AccessorID))
+			continue
+		}
+
+		token, _, err := client.ACL().TokenRead(stub.AccessorID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading token %q: %v", stub.AccessorID, err)
+		}
+		if !c.includeSecrets {
+			token.SecretID = ""
+		}
+		data.Tokens = append(data.Tokens, token)
+	}
+
+	return data, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Export all ACL policies, roles and tokens"
+const help = `
+Usage: consul acl export [options]
+
+  Writes every ACL policy, role and token as a single JSON document to stdout.
+  The output can be loaded into another cluster with "consul acl import". The
+  builtin global-management policy and anonymous token are left out as they
+  exist in every cluster, and legacy tokens are skipped.
+
+  Exporting everything including the token secrets:
+
+      $ consul acl export -include-secrets > acl.json
+`
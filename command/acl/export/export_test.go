@@ -0,0 +1,121 @@
+package aclexport
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/logger"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestExportCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	wopts := &api.WriteOptions{Token: "root"}
+
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "readers", Rules: `key_prefix "" { policy = "read" }`},
+		wopts,
+	)
+	require.NoError(err)
+
+	role, _, err := client.ACL().RoleCreate(
+		&api.ACLRole{Name: "ops", Policies: []*api.ACLRolePolicyLink{{ID: policy.ID}}},
+		wopts,
+	)
+	require.NoError(err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "exported", Roles: []*api.ACLTokenRoleLink{{ID: role.ID}}},
+		wopts,
+	)
+	require.NoError(err)
+
+	export := func(args ...string) *acl.Dataset {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(append([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+		}, args...))
+		require.Equal(0, code, ui.ErrorWriter.String())
+
+		var data acl.Dataset
+		require.NoError(json.Unmarshal(ui.OutputWriter.Bytes(), &data))
+		return &data
+	}
+
+	findToken := func(data *acl.Dataset) *api.ACLToken {
+		for _, tok := range data.Tokens {
+			if tok.AccessorID == token.AccessorID {
+				return tok
+			}
+		}
+		t.Fatalf("token %q not exported", token.AccessorID)
+		return nil
+	}
+
+	data := export()
+	require.Len(data.Policies, 1)
+	require.Equal(policy.Rules, data.Policies[0].Rules)
+	require.Len(data.Roles, 1)
+	require.Equal(role.Name, data.Roles[0].Name)
+	// The master token is exported along with ours, the anonymous token is not.
+	require.Len(data.Tokens, 2)
+	exported := findToken(data)
+	require.Equal("exported", exported.Description)
+	require.Empty(exported.SecretID)
+
+	data = export("-include-secrets")
+	require.Equal(token.SecretID, findToken(data).SecretID)
+
+	// Without acl:write the secrets would be redacted.
+	aclRead, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "acl-read", Rules: `acl = "read"`},
+		wopts,
+	)
+	require.NoError(err)
+	reader, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Policies: []*api.ACLTokenPolicyLink{{ID: aclRead.ID}}},
+		wopts,
+	)
+	require.NoError(err)
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=" + reader.SecretID,
+		"-include-secrets",
+	})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "requires acl:write")
+}
@@ -0,0 +1,348 @@
+package aclimport
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	skipExisting bool
+
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.skipExisting, "skip-existing", false, "Skip policies and "+
+		"roles whose name and tokens whose AccessorID already exist instead of "+
+		"failing. Links to a skipped policy or role are pointed at the existing one")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Must specify exactly one ACL data file")
+		return 1
+	}
+
+	raw, err := helpers.LoadDataSourceMax(args[0], c.testStdin, acl.MaxDatasetSize)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading ACL data: %v", err))
+		return 1
+	}
+
+	var data acl.Dataset
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&data); err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid ACL data: %v", err))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	imp := &importer{client: client, ui: c.UI, skipExisting: c.skipExisting}
+	if err := imp.run(&data); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Info(fmt.Sprintf("Imported %d policies, %d roles and %d tokens",
+		len(imp.createdPolicies), len(imp.createdRoles), len(imp.createdTokens)))
+	if imp.skipped > 0 {
+		c.UI.Info(fmt.Sprintf("Skipped %d objects that already existed", imp.skipped))
+	}
+	if imp.expired > 0 {
+		c.UI.Info(fmt.Sprintf("Skipped %d expired tokens", imp.expired))
+	}
+	return 0
+}
+
+// importer creates the objects of a dataset in dependency order: policies,
+// then roles linking them, then tokens linking both. Policies and roles get
+// new IDs, so the links to them are rewritten as they are created.
+type importer struct {
+	client       *api.Client
+	ui           cli.Ui
+	skipExisting bool
+
+	// policyIDs and roleIDs map the IDs of the dataset to the IDs in this
+	// cluster.
+	policyIDs map[string]string
+	roleIDs   map[string]string
+
+	createdPolicies []string
+	createdRoles    []string
+	createdTokens   []string
+	skipped         int
+	expired         int
+}
+
+func (imp *importer) run(data *acl.Dataset) error {
+	imp.policyIDs = make(map[string]string)
+	imp.roleIDs = make(map[string]string)
+
+	// Everything that already exists is looked up front so that nothing is
+	// created when the import would fail because of it.
+	existingPolicies := make(map[string]string)
+	policies, _, err := imp.client.ACL().PolicyList(nil)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve the policy list: %v", err)
+	}
+	for _, p := range policies {
+		existingPolicies[p.Name] = p.ID
+	}
+
+	existingRoles := make(map[string]string)
+	roles, _, err := imp.client.ACL().RoleList(nil)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve the role list: %v", err)
+	}
+	for _, r := range roles {
+		existingRoles[r.Name] = r.ID
+	}
+
+	existingTokens := make(map[string]struct{})
+	tokens, _, err := imp.client.ACL().TokenList(nil)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve the token list: %v", err)
+	}
+	for _, t := range tokens {
+		existingTokens[t.AccessorID] = struct{}{}
+	}
+
+	var conflicts []string
+	for _, p := range data.Policies {
+		if id, ok := existingPolicies[p.Name]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("policy %q", p.Name))
+			imp.policyIDs[p.ID] = id
+		}
+	}
+	for _, r := range data.Roles {
+		if id, ok := existingRoles[r.Name]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("role %q", r.Name))
+			imp.roleIDs[r.ID] = id
+		}
+	}
+	for _, t := range data.Tokens {
+		if _, ok := existingTokens[t.AccessorID]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("token %q", t.AccessorID))
+		}
+	}
+	if len(conflicts) > 0 && !imp.skipExisting {
+		return fmt.Errorf("Cannot import objects that already exist: %s. "+
+			"Use -skip-existing to skip them", strings.Join(conflicts, ", "))
+	}
+	imp.skipped = len(conflicts)
+
+	if err := imp.create(data, existingTokens); err != nil {
+		if rbErr := imp.rollback(); rbErr != nil {
+			return fmt.Errorf("%v. %v", err, rbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// minExpirationTTL is how long an imported token must still be valid for.
+// The servers refuse to create tokens that expire any sooner.
+const minExpirationTTL = time.Minute
+
+func (imp *importer) create(data *acl.Dataset, existingTokens map[string]struct{}) error {
+	for _, p := range data.Policies {
+		if _, ok := imp.policyIDs[p.ID]; ok {
+			continue
+		}
+		policy, _, err := imp.client.ACL().PolicyCreate(&api.ACLPolicy{
+			Name:        p.Name,
+			Description: p.Description,
+			Rules:       p.Rules,
+			Datacenters: p.Datacenters,
+			Labels:      p.Labels,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create policy %q: %v", p.Name, err)
+		}
+		imp.policyIDs[p.ID] = policy.ID
+		imp.createdPolicies = append(imp.createdPolicies, policy.ID)
+	}
+
+	for _, r := range data.Roles {
+		if _, ok := imp.roleIDs[r.ID]; ok {
+			continue
+		}
+		role, _, err := imp.client.ACL().RoleCreate(&api.ACLRole{
+			Name:              r.Name,
+			Description:       r.Description,
+			Policies:          imp.rolePolicyLinks(r.Policies),
+			ServiceIdentities: r.ServiceIdentities,
+			Labels:            r.Labels,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create role %q: %v", r.Name, err)
+		}
+		imp.roleIDs[r.ID] = role.ID
+		imp.createdRoles = append(imp.createdRoles, role.ID)
+	}
+
+	for _, t := range data.Tokens {
+		if _, ok := existingTokens[t.AccessorID]; ok {
+			continue
+		}
+		if t.ExpirationTime != nil && time.Until(*t.ExpirationTime) < minExpirationTTL {
+			imp.ui.Warn(fmt.Sprintf("Skipping token %q: it expires at %s",
+				t.AccessorID, t.ExpirationTime.Format(time.RFC3339)))
+			imp.expired++
+			continue
+		}
+		token, _, err := imp.client.ACL().TokenCreateWithIDs(&api.ACLToken{
+			AccessorID:        t.AccessorID,
+			SecretID:          t.SecretID,
+			Description:       t.Description,
+			Policies:          imp.tokenPolicyLinks(t.Policies),
+			Roles:             imp.tokenRoleLinks(t.Roles),
+			ServiceIdentities: t.ServiceIdentities,
+			Local:             t.Local,
+			ExpirationTime:    t.ExpirationTime,
+			Labels:            t.Labels,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create token %q: %v", t.AccessorID, err)
+		}
+		imp.createdTokens = append(imp.createdTokens, token.AccessorID)
+	}
+
+	return nil
+}
+
+// link returns the ID and name to link an object of the dataset by. Links to
+// imported objects use their new IDs. Other links, such as to the builtin
+// global-management policy, are resolved by name.
+func link(ids map[string]string, id, name string) (string, string) {
+	if newID, ok := ids[id]; ok {
+		return newID, ""
+	}
+	if name != "" {
+		return "", name
+	}
+	return id, ""
+}
+
+func (imp *importer) tokenPolicyLinks(links []*api.ACLTokenPolicyLink) []*api.ACLTokenPolicyLink {
+	var out []*api.ACLTokenPolicyLink
+	for _, l := range links {
+		id, name := link(imp.policyIDs, l.ID, l.Name)
+		out = append(out, &api.ACLTokenPolicyLink{ID: id, Name: name})
+	}
+	return out
+}
+
+func (imp *importer) rolePolicyLinks(links []*api.ACLRolePolicyLink) []*api.ACLRolePolicyLink {
+	var out []*api.ACLRolePolicyLink
+	for _, l := range links {
+		id, name := link(imp.policyIDs, l.ID, l.Name)
+		out = append(out, &api.ACLRolePolicyLink{ID: id, Name: name})
+	}
+	return out
+}
+
+func (imp *importer) tokenRoleLinks(links []*api.ACLTokenRoleLink) []*api.ACLTokenRoleLink {
+	var out []*api.ACLTokenRoleLink
+	for _, l := range links {
+		id, name := link(imp.roleIDs, l.ID, l.Name)
+		out = append(out, &api.ACLTokenRoleLink{ID: id, Name: name})
+	}
+	return out
+}
+
+// rollback deletes the objects created so far, in reverse dependency order,
+// so that a failed import can be retried. It returns an error naming every
+// object that could not be deleted.
+func (imp *importer) rollback() error {
+	var failed []string
+	for _, id := range imp.createdTokens {
+		if _, err := imp.client.ACL().TokenDelete(id, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("token %q (%v)", id, err))
+		}
+	}
+	for _, id := range imp.createdRoles {
+		if _, err := imp.client.ACL().RoleDelete(id, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("role %q (%v)", id, err))
+		}
+	}
+	for _, id := range imp.createdPolicies {
+		if _, err := imp.client.ACL().PolicyDelete(id, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("policy %q (%v)", id, err))
+		}
+	}
+	imp.createdTokens, imp.createdRoles, imp.createdPolicies = nil, nil, nil
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Failed to remove the objects created before the "+
+			"failure, they must be deleted manually: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Import ACL policies, roles and tokens"
+const help = `
+Usage: consul acl import [options] DATA
+
+  Creates the ACL policies, roles and tokens written by "consul acl export".
+  DATA is the path of the file prefixed with '@', or '-' to read it from
+  stdin. Policies are created first, then roles and then tokens, and the
+  links between them are rewritten to the IDs of the new objects. Tokens
+  keep their AccessorIDs, and their SecretIDs if those were exported.
+
+  Tokens that have already expired, or expire within a minute, are skipped
+  with a warning. If any object fails to be created the ones created before
+  it are removed again, and any that cannot be removed are listed in the
+  error. By default nothing is imported when an object already exists:
+
+      $ consul acl import @acl.json
+
+  Skipping the objects that already exist instead:
+
+      $ consul acl import -skip-existing @acl.json
+`
@@ -0,0 +1,150 @@
+package aclimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/logger"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	qopts := &api.QueryOptions{Token: "root"}
+
+	// IDs from the exporting cluster, the policy and role get new ones.
+	data := `{
+		"Policies": [
+			{"ID": "3a4e52d1-8a5d-4c49-a2d0-9a3e8a1a5c11", "Name": "readers", "Rules": "key_prefix \"\" { policy = \"read\" }"}
+		],
+		"Roles": [
+			{"ID": "f1c2b5a4-7d2e-4b8a-9d6c-0e5f4a3b2c11", "Name": "ops", "Policies": [{"ID": "3a4e52d1-8a5d-4c49-a2d0-9a3e8a1a5c11", "Name": "readers"}]}
+		],
+		"Tokens": [
+			{
+				"AccessorID": "6a1253d2-1785-24fd-91c2-f8e78c745511",
+				"SecretID": "45a3bd52-07c7-47a4-52fd-0745e0cfe967",
+				"Description": "imported",
+				"Policies": [{"ID": "00000000-0000-0000-0000-000000000001", "Name": "global-management"}],
+				"Roles": [{"ID": "f1c2b5a4-7d2e-4b8a-9d6c-0e5f4a3b2c11", "Name": "ops"}]
+			}
+		]
+	}`
+
+	run := func(args ...string) (int, *cli.MockUi) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		cmd.testStdin = strings.NewReader(data)
+		code := cmd.Run(append([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+		}, args...))
+		return code, ui
+	}
+
+	code, ui := run("-")
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Imported 1 policies, 1 roles and 1 tokens")
+
+	token, _, err := client.ACL().TokenRead("6a1253d2-1785-24fd-91c2-f8e78c745511", qopts)
+	require.NoError(t, err)
+	require.Equal(t, "45a3bd52-07c7-47a4-52fd-0745e0cfe967", token.SecretID)
+	require.Len(t, token.Policies, 1)
+	require.Equal(t, "global-management", token.Policies[0].Name)
+	require.Len(t, token.Roles, 1)
+	require.Equal(t, "ops", token.Roles[0].Name)
+	require.NotEqual(t, "f1c2b5a4-7d2e-4b8a-9d6c-0e5f4a3b2c11", token.Roles[0].ID)
+
+	role, _, err := client.ACL().RoleRead(token.Roles[0].ID, qopts)
+	require.NoError(t, err)
+	require.Len(t, role.Policies, 1)
+	require.Equal(t, "readers", role.Policies[0].Name)
+
+	t.Run("existing objects", func(t *testing.T) {
+		require := require.New(t)
+		code, ui := run("-")
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), `policy "readers"`)
+		require.Contains(ui.ErrorWriter.String(), "-skip-existing")
+
+		code, ui = run("-skip-existing", "-")
+		require.Equal(0, code, ui.ErrorWriter.String())
+		require.Contains(ui.OutputWriter.String(), "Imported 0 policies, 0 roles and 0 tokens")
+		require.Contains(ui.OutputWriter.String(), "Skipped 3 objects")
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		require := require.New(t)
+		data = `{
+			"Policies": [{"ID": "b0e1c6a2-5f3d-4e7c-8a9b-1c2d3e4f5a11", "Name": "writers"}],
+			"Tokens": [{"AccessorID": "9c8b7a6d-5e4f-4a3b-8c2d-1e0f9a8b7c11", "Policies": [{"Name": "missing"}]}]
+		}`
+
+		code, ui := run("-")
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), "Failed to create token")
+
+		policies, _, err := client.ACL().PolicyList(qopts)
+		require.NoError(err)
+		for _, policy := range policies {
+			require.NotEqual("writers", policy.Name)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		require := require.New(t)
+		data = `{
+			"Tokens": [{
+				"AccessorID": "2f1e0d9c-8b7a-4c6d-9e5f-4a3b2c1d0e11",
+				"Policies": [{"Name": "global-management"}],
+				"ExpirationTime": "2019-01-01T00:00:00Z"
+			}]
+		}`
+
+		code, ui := run("-")
+		require.Equal(0, code, ui.ErrorWriter.String())
+		require.Contains(ui.ErrorWriter.String(), `Skipping token "2f1e0d9c-8b7a-4c6d-9e5f-4a3b2c1d0e11"`)
+		require.Contains(ui.OutputWriter.String(), "Imported 0 policies, 0 roles and 0 tokens")
+		require.Contains(ui.OutputWriter.String(), "Skipped 1 expired tokens")
+
+		_, _, err := client.ACL().TokenRead("2f1e0d9c-8b7a-4c6d-9e5f-4a3b2c1d0e11", qopts)
+		require.Error(err)
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		require := require.New(t)
+		data = `{"BindingRules": []}`
+
+		code, ui := run("-")
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), "Invalid ACL data")
+	})
+}
@@ -28,6 +28,7 @@ type cmd struct {
 	name        string
 	description string
 	datacenters []string
+	labels      map[string]string
 	rules       string
 
 	fromToken     string
@@ -45,6 +46,8 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.description, "description", "", "A description of the policy")
 	c.flags.Var((*flags.AppendSliceValue)(&c.datacenters), "valid-datacenter", "Datacenter "+
 		"that the policy should be valid within. This flag may be specified multiple times")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Label to attach to "+
+		"the policy, in the form key=value. May be specified multiple times")
 	c.flags.StringVar(&c.rules, "rules", "", "The policy rules. May be prefixed with '@' "+
 		"to indicate that the value is a file path to load the rules from. '-' may also be "+
 		"given to indicate that the rules are available on stdin")
@@ -66,7 +69,7 @@ func (c *cmd) getRules(client *api.Client) (string, error) {
 	}
 
 	if c.fromToken != "" {
-		tokenID, err := helpers.LoadDataSource(c.fromToken, c.testStdin)
+		tokenID, err := helpers.LoadDataSourceMax(c.fromToken, c.testStdin, aclhelpers.MaxDataSize)
 		if err != nil {
 			return "", fmt.Errorf("Invalid -from-token value: %v", err)
 		}
@@ -80,7 +83,7 @@ func (c *cmd) getRules(client *api.Client) (string, error) {
 		return string(translated), err
 	}
 
-	return helpers.LoadDataSource(c.rules, c.testStdin)
+	return helpers.LoadDataSourceMax(c.rules, c.testStdin, aclhelpers.MaxDataSize)
 }
 
 func (c *cmd) Run(args []string) int {
@@ -110,6 +113,7 @@ func (c *cmd) Run(args []string) int {
 		Name:        c.name,
 		Description: c.description,
 		Datacenters: c.datacenters,
+		Labels:      c.labels,
 		Rules:       rules,
 	}
 
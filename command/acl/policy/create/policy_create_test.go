@@ -1,12 +1,14 @@
 package policycreate
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/logger"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -61,3 +63,43 @@ func TestPolicyCreateCommand(t *testing.T) {
 	assert.Equal(code, 0)
 	assert.Empty(ui.ErrorWriter.String())
 }
+
+func TestPolicyCreateCommand_rulesTooLarge(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	rules := bytes.Repeat([]byte("#"), acl.MaxDataSize+1)
+	err := ioutil.WriteFile(testDir+"/rules.hcl", rules, 0644)
+	assert.NoError(err)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-name=foobar",
+		"-rules=@" + testDir + "/rules.hcl",
+	}
+
+	code := cmd.Run(args)
+	assert.Equal(code, 1)
+	assert.Contains(ui.ErrorWriter.String(), "exceeds the 524288 byte limit")
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
@@ -23,6 +24,7 @@ type cmd struct {
 
 	policyID   string
 	policyName string
+	labels     map[string]string
 }
 
 func (c *cmd) init() {
@@ -31,6 +33,9 @@ func (c *cmd) init() {
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple policy IDs")
 	c.flags.StringVar(&c.policyName, "name", "", "The name of the policy to delete.")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Delete every policy "+
+		"carrying this label, in the form key=value. May be specified multiple "+
+		"times, in which case all labels must match. Cannot be used with -id or -name")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -42,8 +47,13 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	if c.policyID == "" && c.policyName == "" {
-		c.UI.Error(fmt.Sprintf("Must specify either the -id or -name parameters"))
+	if c.policyID == "" && c.policyName == "" && len(c.labels) == 0 {
+		c.UI.Error(fmt.Sprintf("Must specify one of the -id, -name or -label parameters"))
+		return 1
+	}
+
+	if len(c.labels) > 0 && (c.policyID != "" || c.policyName != "") {
+		c.UI.Error(fmt.Sprintf("Cannot specify -label with the -id or -name parameters"))
 		return 1
 	}
 
@@ -53,6 +63,10 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if len(c.labels) > 0 {
+		return c.deleteByLabel(client)
+	}
+
 	var policyID string
 	if c.policyID != "" {
 		policyID, err = acl.GetPolicyIDFromPartial(client, c.policyID)
@@ -73,6 +87,30 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) deleteByLabel(client *api.Client) int {
+	policies, _, err := client.ACL().PolicyList(&api.QueryOptions{Labels: c.labels})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the policy list: %v", err))
+		return 1
+	}
+
+	if len(policies) == 0 {
+		c.UI.Info(fmt.Sprintf("No policies matched the given labels"))
+		return 0
+	}
+
+	result := 0
+	for _, policy := range policies {
+		if _, err := client.ACL().PolicyDelete(policy.ID, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Error deleting policy %q: %v", policy.ID, err))
+			result = 1
+			continue
+		}
+		c.UI.Info(fmt.Sprintf("Policy %q deleted successfully", policy.ID))
+	}
+	return result
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -95,4 +133,8 @@ Usage: consul acl policy delete [options] -id POLICY
 
         $ consul acl policy delete -id b6b856da-5193-4e78-845a-7d61ca8371ba
 
+    Delete every policy carrying a label:
+
+        $ consul acl policy delete -label env=staging
+
 `
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
@@ -21,6 +22,7 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
+	labels   map[string]string
 	showMeta bool
 }
 
@@ -28,6 +30,9 @@ func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that policy metadata such "+
 		"as the content hash and raft indices should be shown for each entry")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Only list policies "+
+		"carrying this label, in the form key=value. May be specified multiple "+
+		"times, in which case all labels must match")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -46,7 +51,7 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	policies, _, err := client.ACL().PolicyList(nil)
+	policies, _, err := client.ACL().PolicyList(&api.QueryOptions{Labels: c.labels})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to retrieve the policy list: %v", err))
 		return 1
@@ -76,4 +81,8 @@ Usage: consul acl policy list [options]
     Example:
 
         $ consul acl policy list
+
+    List only the policies carrying a label:
+
+        $ consul acl policy list -label team=payments
 `
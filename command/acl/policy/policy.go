@@ -54,5 +54,9 @@ Usage: consul acl policy <subcommand> [options] [args]
 
     $ consul acl policy delete -name "my-policy"
 
+  Validate the rules of a policy without contacting a server
+
+    $ consul acl policy validate @rules.hcl
+
   For more examples, ask for subcommand help or view the documentation.
 `
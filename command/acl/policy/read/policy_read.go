@@ -24,6 +24,7 @@ type cmd struct {
 	policyID   string
 	policyName string
 	showMeta   bool
+	showUsage  bool
 }
 
 func (c *cmd) init() {
@@ -34,6 +35,9 @@ func (c *cmd) init() {
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple policy IDs")
 	c.flags.StringVar(&c.policyName, "name", "", "The name of the policy to read.")
+	c.flags.BoolVar(&c.showUsage, "show-usage", false, "Also list the tokens and "+
+		"roles linking to the policy. Tokens that only receive the policy through "+
+		"a role are not listed, and neither are local tokens of other datacenters")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -73,6 +77,15 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 	acl.PrintPolicy(policy, c.UI, c.showMeta)
+
+	if c.showUsage {
+		usage, _, err := client.ACL().PolicyUsage(policyID, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading the usage of policy %q: %v", policyID, err))
+			return 1
+		}
+		acl.PrintPolicyUsage(usage, c.UI)
+	}
 	return 0
 }
 
@@ -99,4 +112,8 @@ Usage: consul acl policy read [options] POLICY
 
         $ consul acl policy read -name my-policy
 
+    Read and list the tokens and roles using it:
+
+        $ consul acl policy read -name my-policy -show-usage
+
 `
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPolicyReadCommand_noTabs(t *testing.T) {
@@ -70,3 +71,53 @@ func TestPolicyReadCommand(t *testing.T) {
 	assert.Contains(output, fmt.Sprintf("test-policy"))
 	assert.Contains(output, policy.ID)
 }
+
+func TestPolicyReadCommand_showUsage(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	wopts := &api.WriteOptions{Token: "root"}
+
+	policy, _, err := client.ACL().PolicyCreate(&api.ACLPolicy{Name: "test-policy"}, wopts)
+	require.NoError(err)
+
+	role, _, err := client.ACL().RoleCreate(&api.ACLRole{
+		Name:     "test-role",
+		Policies: []*api.ACLRolePolicyLink{{ID: policy.ID}},
+	}, wopts)
+	require.NoError(err)
+
+	token, _, err := client.ACL().TokenCreate(&api.ACLToken{
+		Description: "test-token",
+		Policies:    []*api.ACLTokenPolicyLink{{Name: policy.Name}},
+	}, wopts)
+	require.NoError(err)
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-name=test-policy",
+		"-show-usage",
+	})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(output, fmt.Sprintf("%s - test-token", token.AccessorID))
+	require.Contains(output, fmt.Sprintf("%s - test-role", role.ID))
+}
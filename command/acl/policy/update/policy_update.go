@@ -30,6 +30,7 @@ type cmd struct {
 	descriptionSet bool
 	description    string
 	datacenters    []string
+	labels         map[string]string
 	rulesSet       bool
 	rules          string
 	noMerge        bool
@@ -48,6 +49,10 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.description, "description", "", "A description of the policy")
 	c.flags.Var((*flags.AppendSliceValue)(&c.datacenters), "valid-datacenter", "Datacenter "+
 		"that the policy should be valid within. This flag may be specified multiple times")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Label to set on the "+
+		"policy, in the form key=value. Unless -no-merge is given labels are merged "+
+		"with the existing labels and an empty value removes the label. May be "+
+		"specified multiple times")
 	c.flags.StringVar(&c.rules, "rules", "", "The policy rules. May be prefixed with '@' "+
 		"to indicate that the value is a file path to load the rules from. '-' may also be "+
 		"given to indicate that the rules are available on stdin")
@@ -100,7 +105,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	rules, err := helpers.LoadDataSource(c.rules, c.testStdin)
+	rules, err := helpers.LoadDataSourceMax(c.rules, c.testStdin, acl.MaxDataSize)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid -rules value: %v", err))
+		return 1
+	}
 
 	var updated *api.ACLPolicy
 	if c.noMerge {
@@ -109,6 +118,7 @@ func (c *cmd) Run(args []string) int {
 			Name:        c.name,
 			Description: c.description,
 			Datacenters: c.datacenters,
+			Labels:      acl.MergeLabels(nil, c.labels),
 			Rules:       rules,
 		}
 	} else {
@@ -123,6 +133,7 @@ func (c *cmd) Run(args []string) int {
 			Name:        policy.Name,
 			Description: policy.Description,
 			Datacenters: policy.Datacenters,
+			Labels:      policy.Labels,
 			Rules:       policy.Rules,
 		}
 
@@ -138,6 +149,9 @@ func (c *cmd) Run(args []string) int {
 		if c.datacenters != nil {
 			updated.Datacenters = c.datacenters
 		}
+		if len(c.labels) > 0 {
+			updated.Labels = acl.MergeLabels(policy.Labels, c.labels)
+		}
 	}
 
 	policy, _, err := client.ACL().PolicyUpdate(updated, nil)
@@ -0,0 +1,180 @@
+package policyvalidate
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/acl"
+	aclhelpers "github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	help  string
+
+	legacy bool
+	quiet  bool
+
+	// testStdin is the input for testing
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.legacy, "legacy", false, "Validate the rules using the "+
+		"legacy syntax of ACL tokens instead of the current policy syntax")
+	c.flags.BoolVar(&c.quiet, "quiet", false, "When given, a successful run "+
+		"will produce no output")
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	rules, err := c.dataFromArgs(c.flags.Args())
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %v", err))
+		return 1
+	}
+
+	syntax := acl.SyntaxCurrent
+	if c.legacy {
+		syntax = acl.SyntaxLegacy
+	}
+
+	// Sentinel policies cannot be checked without a server so a nil
+	// evaluator is used, just like servers without Sentinel support do.
+	if _, err := acl.NewPolicyFromSource("", 0, rules, syntax, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Rules validation failed: %v", err))
+		return 1
+	}
+
+	unknown, err := unknownStanzas(rules)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Rules validation failed: %v", err))
+		return 1
+	}
+	if len(unknown) > 0 {
+		c.UI.Error(fmt.Sprintf("Rules validation failed: unknown rule types: %s",
+			strings.Join(unknown, ", ")))
+		return 1
+	}
+
+	if !c.quiet {
+		c.UI.Output("Rules are valid!")
+	}
+	return 0
+}
+
+func (c *cmd) dataFromArgs(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "", fmt.Errorf("Missing RULES argument")
+	case 1:
+		return helpers.LoadDataSourceMax(args[0], c.testStdin, aclhelpers.MaxDataSize)
+	default:
+		return "", fmt.Errorf("Too many arguments: expected 1 got %d", len(args))
+	}
+}
+
+// unknownStanzas returns the sorted top level keys of the rules that do not
+// correspond to any rule type. Decoding the rules silently ignores these, so
+// a misspelt rule type would otherwise go unnoticed and grant nothing.
+func unknownStanzas(rules string) ([]string, error) {
+	if rules == "" {
+		return nil, nil
+	}
+
+	file, err := hcl.Parse(rules)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse ACL rules: %v", err)
+	}
+	list, ok := file.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, nil
+	}
+
+	known := make(map[string]struct{})
+	policyType := reflect.TypeOf(acl.Policy{})
+	for i := 0; i < policyType.NumField(); i++ {
+		name := strings.Split(policyType.Field(i).Tag.Get("hcl"), ",")[0]
+		if name != "" {
+			known[name] = struct{}{}
+		}
+	}
+
+	seen := make(map[string]struct{})
+	var unknown []string
+	for _, item := range list.Items {
+		if len(item.Keys) == 0 {
+			continue
+		}
+		key, ok := item.Keys[0].Token.Value().(string)
+		if !ok {
+			key = item.Keys[0].Token.Text
+		}
+		if _, ok := known[key]; ok {
+			continue
+		}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Validate ACL rules without contacting a server"
+const help = `
+Usage: consul acl policy validate [options] RULES
+
+  Checks that ACL rules are syntactically and semantically valid, the same way
+  servers check the rules of a policy when it is created or updated. Rule
+  types that do not exist are also reported, as servers silently ignore them.
+  No server is contacted, which makes this suitable for gating changes to
+  policies in CI pipelines. The exit code is 0 if the rules are valid and 1
+  otherwise.
+
+  Validate rules within a file:
+
+      $ consul acl policy validate @rules.hcl
+
+  Validate rules from stdin:
+
+      $ consul acl policy validate -
+
+  Validate rules from a string argument:
+
+      $ consul acl policy validate 'key_prefix "" { policy = "read" }'
+
+  Validate legacy token rules:
+
+      $ consul acl policy validate -legacy @legacy-rules.hcl
+`
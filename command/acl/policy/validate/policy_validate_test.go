@@ -0,0 +1,116 @@
+package policyvalidate
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyValidateCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestPolicyValidateCommand(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	cases := []struct {
+		name   string
+		args   []string
+		rules  string
+		stdin  bool
+		errMsg string
+	}{
+		{
+			name:  "valid",
+			rules: `service_prefix "" { policy = "read" } key "foo" { policy = "list" } operator = "read"`,
+		},
+		{
+			name:  "valid json",
+			rules: `{"node_prefix": {"": {"policy": "write"}}}`,
+		},
+		{
+			name:  "valid from stdin",
+			rules: `agent_prefix "" { policy = "read" }`,
+			stdin: true,
+		},
+		{
+			name:  "empty",
+			rules: "",
+		},
+		{
+			name:   "syntax error",
+			rules:  `service "foo" { policy = "read" `,
+			errMsg: "Failed to parse ACL rules",
+		},
+		{
+			name:   "invalid disposition",
+			rules:  `service "foo" { policy = "admin" }`,
+			errMsg: "Invalid service policy",
+		},
+		{
+			name:   "unknown rule type",
+			rules:  `servce "foo" { policy = "read" } node "" { policy = "read" }`,
+			errMsg: "unknown rule types: servce",
+		},
+		{
+			name:  "legacy",
+			args:  []string{"-legacy"},
+			rules: `key "" { policy = "read" }`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			ui := cli.NewMockUi()
+			cmd := New(ui)
+
+			args := append([]string{}, tc.args...)
+			if tc.stdin {
+				cmd.testStdin = strings.NewReader(tc.rules)
+				args = append(args, "-")
+			} else {
+				path := testDir + "/" + strings.Replace(tc.name, " ", "-", -1) + ".hcl"
+				assert.NoError(ioutil.WriteFile(path, []byte(tc.rules), 0644))
+				args = append(args, "@"+path)
+			}
+
+			code := cmd.Run(args)
+			if tc.errMsg == "" {
+				assert.Equal(0, code)
+				assert.Empty(ui.ErrorWriter.String())
+				assert.Contains(ui.OutputWriter.String(), "Rules are valid!")
+			} else {
+				assert.Equal(1, code)
+				assert.Contains(ui.ErrorWriter.String(), tc.errMsg)
+			}
+		})
+	}
+}
+
+func TestPolicyValidateCommand_quiet(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	code := cmd.Run([]string{"-quiet", `node_prefix "" { policy = "read" }`})
+	assert.Equal(0, code)
+	assert.Empty(ui.OutputWriter.String())
+	assert.Empty(ui.ErrorWriter.String())
+}
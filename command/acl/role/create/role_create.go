@@ -0,0 +1,140 @@
+package rolecreate
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	name          string
+	description   string
+	policyIDs     []string
+	policyNames   []string
+	serviceIdents []string
+	labels        map[string]string
+	showMeta      bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that role metadata such "+
+		"as the content hash and raft indices should be shown for each entry")
+	c.flags.StringVar(&c.name, "name", "", "The new role's name. This flag is required.")
+	c.flags.StringVar(&c.description, "description", "", "A description of the role")
+	c.flags.Var((*flags.AppendSliceValue)(&c.policyIDs), "policy-id", "ID of a "+
+		"policy to use for this role. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.policyNames), "policy-name", "Name of a "+
+		"policy to use for this role. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.serviceIdents), "service-identity", "Name of a "+
+		"service identity to use for this role, optionally followed by a colon and a "+
+		"comma separated list of datacenters, e.g. web:dc1,dc2. May be specified "+
+		"multiple times")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Label to attach to "+
+		"the role, in the form key=value. May be specified multiple times")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.name == "" {
+		c.UI.Error(fmt.Sprintf("Missing require '-name' flag"))
+		c.UI.Error(c.Help())
+		return 1
+	}
+
+	serviceIdents, err := acl.ExtractServiceIdentities(c.serviceIdents)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	newRole := &api.ACLRole{
+		Name:              c.name,
+		Description:       c.description,
+		ServiceIdentities: serviceIdents,
+		Labels:            c.labels,
+	}
+
+	for _, policyName := range c.policyNames {
+		// We could resolve names to IDs here but there isn't any reason why its would be better
+		// than allowing the agent to do it.
+		newRole.Policies = append(newRole.Policies, &api.ACLRolePolicyLink{Name: policyName})
+	}
+
+	for _, policyID := range c.policyIDs {
+		policyID, err := acl.GetPolicyIDFromPartial(client, policyID)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error resolving policy ID %s: %v", policyID, err))
+			return 1
+		}
+		newRole.Policies = append(newRole.Policies, &api.ACLRolePolicyLink{ID: policyID})
+	}
+
+	role, _, err := client.ACL().RoleCreate(newRole, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to create new role: %v", err))
+		return 1
+	}
+
+	acl.PrintRole(role, c.UI, c.showMeta)
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Create an ACL Role"
+const help = `
+Usage: consul acl role create -name NAME [options]
+
+    When creating a new role policies may be linked using either the -policy-id
+    or the -policy-name options. When specifying policies by IDs you may use a
+    unique prefix of the UUID as a shortcut for specifying the entire UUID.
+    Service identities grant the privileges needed to register the named
+    service and its sidecar proxy, optionally limited to some datacenters.
+
+    Create a new role:
+
+        $ consul acl role create -name "new-role" \
+                                 -description "This is an example role" \
+                                 -policy-id b52fc3de-5 \
+                                 -policy-name "acl-replication"
+
+    Create a new role for the "web" service in dc1 and dc2:
+
+        $ consul acl role create -name "web" \
+                                 -service-identity "web:dc1,dc2"
+`
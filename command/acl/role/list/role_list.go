@@ -0,0 +1,88 @@
+package rolelist
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	labels   map[string]string
+	showMeta bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that role metadata such "+
+		"as the content hash and raft indices should be shown for each entry")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Only list roles "+
+		"carrying this label, in the form key=value. May be specified multiple "+
+		"times, in which case all labels must match")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	roles, _, err := client.ACL().RoleList(&api.QueryOptions{Labels: c.labels})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the role list: %v", err))
+		return 1
+	}
+
+	for _, role := range roles {
+		acl.PrintRoleListEntry(role, c.UI, c.showMeta)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Lists ACL Roles"
+const help = `
+Usage: consul acl role list [options]
+
+    Lists all the ACL roles
+
+    Example:
+
+        $ consul acl role list
+
+    List only the roles carrying a label:
+
+        $ consul acl role list -label team=payments
+`
@@ -0,0 +1,79 @@
+package rolelist
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/logger"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleListCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestRoleListCommand(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	client := a.Client()
+
+	var roleIDs []string
+
+	// Create a couple roles to list
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("test-role-%d", i)
+
+		role, _, err := client.ACL().RoleCreate(
+			&api.ACLRole{Name: name},
+			&api.WriteOptions{Token: "root"},
+		)
+		assert.NoError(err)
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+	}
+
+	code := cmd.Run(args)
+	assert.Equal(code, 0)
+	assert.Empty(ui.ErrorWriter.String())
+	output := ui.OutputWriter.String()
+
+	for i, v := range roleIDs {
+		assert.Contains(output, fmt.Sprintf("test-role-%d", i))
+		assert.Contains(output, v)
+	}
+}
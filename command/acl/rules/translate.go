@@ -92,7 +92,7 @@ func (c *cmd) dataFromArgs(args []string) (string, error) {
 	case 0:
 		return "", fmt.Errorf("Missing TRANSLATE argument")
 	case 1:
-		data, err := helpers.LoadDataSource(args[0], c.testStdin)
+		data, err := helpers.LoadDataSourceMax(args[0], c.testStdin, aclhelpers.MaxDataSize)
 		if err != nil {
 			return "", err
 		}
@@ -0,0 +1,118 @@
+package acltest
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	tokenID  string
+	resource string
+	segment  string
+	access   string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.tokenID, "id", "", "The Accessor ID of the token to test. "+
+		"It may be specified as a unique ID prefix but will error if the prefix "+
+		"matches multiple token Accessor IDs. Defaults to the token used to make "+
+		"the request")
+	c.flags.StringVar(&c.resource, "resource", "", "The resource to test access to. "+
+		"One of acl, agent, event, intention, key, keyring, node, operator, query, "+
+		"service or session")
+	c.flags.StringVar(&c.segment, "segment", "", "The name of the object within the "+
+		"resource, such as a key, a node or a service name")
+	c.flags.StringVar(&c.access, "access", "read", "The access level to test. One "+
+		"of read, list or write. The list level is only valid for the key resource")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.resource == "" {
+		c.UI.Error(fmt.Sprintf("Must specify the -resource parameter"))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	tokenID := ""
+	if c.tokenID != "" {
+		tokenID, err = acl.GetTokenIDFromPartial(client, c.tokenID)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error determining token ID: %v", err))
+			return 1
+		}
+	}
+
+	authz, _, err := client.ACL().Authorize(tokenID, c.resource, c.segment, c.access, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error testing access: %v", err))
+		return 1
+	}
+
+	decision := "denied"
+	if authz.Allow {
+		decision = "allowed"
+	}
+	c.UI.Info(fmt.Sprintf("Decision:        %s", decision))
+	if authz.Rule != "" {
+		c.UI.Info(fmt.Sprintf("Matching Rule:   %s", authz.Rule))
+	} else {
+		c.UI.Info(fmt.Sprintf("Matching Rule:   none, decided by the default policy %q", authz.DefaultPolicy))
+	}
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Test whether a token is granted access to a resource"
+const help = `
+Usage: consul acl test [options] -resource RESOURCE [-segment NAME] [-access LEVEL]
+
+  This command asks the servers whether a token would be granted access to
+  a resource. The token is resolved and its policies enforced the same way
+  as for any other request, and the rule that made the decision is printed
+  alongside it. Testing a token other than the one used to make the request
+  requires the acl:read privilege.
+
+  Testing whether a token can write a key:
+
+          $ consul acl test -id 4be56c77 -resource key -segment foo/bar -access write
+
+  Testing whether the current token can discover a service:
+
+          $ consul acl test -resource service -segment web
+`
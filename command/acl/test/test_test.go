@@ -0,0 +1,115 @@
+package acltest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/logger"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTestCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		default_policy = "deny"
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "kv-writer", Rules: `key_prefix "foo/" { policy = "write" }`},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Policies: []*api.ACLTokenPolicyLink{&api.ACLTokenPolicyLink{ID: policy.ID}}},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(err)
+
+	t.Run("allowed", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-id=" + token.AccessorID[:8],
+			"-resource=key",
+			"-segment=foo/bar",
+			"-access=write",
+		})
+		require.Equal(0, code, ui.ErrorWriter.String())
+		output := ui.OutputWriter.String()
+		require.Contains(output, "allowed")
+		require.Contains(output, `key_prefix "foo/" { policy = "write" }`)
+	})
+
+	t.Run("denied by default policy", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + token.SecretID,
+			"-resource=service",
+			"-segment=web",
+		})
+		require.Equal(0, code, ui.ErrorWriter.String())
+		output := ui.OutputWriter.String()
+		require.Contains(output, "denied")
+		require.Contains(output, `default policy "deny"`)
+	})
+
+	t.Run("missing resource", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+		})
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), "-resource")
+	})
+
+	t.Run("invalid access level", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-resource=service",
+			"-access=list",
+		})
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), "Error testing access")
+	})
+}
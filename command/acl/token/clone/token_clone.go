@@ -23,6 +23,7 @@ type cmd struct {
 
 	tokenID     string
 	description string
+	utc         bool
 }
 
 func (c *cmd) init() {
@@ -32,6 +33,7 @@ func (c *cmd) init() {
 		"matches multiple token Accessor IDs. The special value of 'anonymous' may "+
 		"be provided instead of the anonymous tokens accessor ID")
 	c.flags.StringVar(&c.description, "description", "", "A description of the new cloned token")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of the local time zone")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -67,7 +69,7 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	c.UI.Info("Token cloned successfully.")
-	acl.PrintToken(token, c.UI, false)
+	acl.PrintToken(token, c.UI, false, c.utc)
 	return 0
 }
 
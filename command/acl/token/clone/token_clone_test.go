@@ -165,4 +165,23 @@ func TestTokenCloneCommand(t *testing.T) {
 		req.Equal(cloned.Local, apiToken.Local)
 		req.Equal(cloned.Policies, apiToken.Policies)
 	})
+
+	// clone rendering timestamps in UTC
+	t.Run("UTC", func(t *testing.T) {
+		req := require.New(t)
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-id=" + token.AccessorID,
+			"-token=root",
+			"-utc",
+		}
+
+		code := cmd.Run(args)
+		req.Equal(code, 0)
+		req.Empty(ui.ErrorWriter.String())
+		req.Regexp(`Create Time:  \S+Z \(`, ui.OutputWriter.String())
+	})
 }
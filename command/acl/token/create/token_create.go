@@ -3,6 +3,7 @@ package tokencreate
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
@@ -22,23 +23,42 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	policyIDs   []string
-	policyNames []string
-	description string
-	local       bool
-	showMeta    bool
+	policyIDs     []string
+	policyNames   []string
+	roleIDs       []string
+	roleNames     []string
+	serviceIdents []string
+	description   string
+	labels        map[string]string
+	local         bool
+	expirationTTL time.Duration
+	showMeta      bool
+	utc           bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that token metadata such "+
 		"as the content hash and raft indices should be shown for each entry")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of the local time zone")
 	c.flags.BoolVar(&c.local, "local", false, "Create this as a datacenter local token")
 	c.flags.StringVar(&c.description, "description", "", "A description of the token")
+	c.flags.DurationVar(&c.expirationTTL, "expires-ttl", 0, "Duration of time this "+
+		"token should be valid for, e.g. 24h. The token is deleted once it expires")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Label to attach to "+
+		"the token, in the form key=value. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.policyIDs), "policy-id", "ID of a "+
 		"policy to use for this token. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.policyNames), "policy-name", "Name of a "+
 		"policy to use for this token. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.roleIDs), "role-id", "ID of a "+
+		"role to use for this token. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.roleNames), "role-name", "Name of a "+
+		"role to use for this token. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.serviceIdents), "service-identity", "Name of a "+
+		"service identity to use for this token, optionally followed by a colon and a "+
+		"comma separated list of datacenters, e.g. web:dc1,dc2. May be specified "+
+		"multiple times")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -50,8 +70,15 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 {
-		c.UI.Error(fmt.Sprintf("Cannot create a token without specifying -policy-name or -policy-id at least once"))
+	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 &&
+		len(c.roleNames) == 0 && len(c.roleIDs) == 0 && len(c.serviceIdents) == 0 {
+		c.UI.Error(fmt.Sprintf("Cannot create a token without specifying -policy-name, -policy-id, -role-name, -role-id or -service-identity at least once"))
+		return 1
+	}
+
+	serviceIdents, err := acl.ExtractServiceIdentities(c.serviceIdents)
+	if err != nil {
+		c.UI.Error(err.Error())
 		return 1
 	}
 
@@ -62,8 +89,11 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	newToken := &api.ACLToken{
-		Description: c.description,
-		Local:       c.local,
+		Description:       c.description,
+		Local:             c.local,
+		Labels:            c.labels,
+		ServiceIdentities: serviceIdents,
+		ExpirationTTL:     c.expirationTTL,
 	}
 
 	for _, policyName := range c.policyNames {
@@ -81,13 +111,28 @@ func (c *cmd) Run(args []string) int {
 		newToken.Policies = append(newToken.Policies, &api.ACLTokenPolicyLink{ID: policyID})
 	}
 
+	for _, roleName := range c.roleNames {
+		// We could resolve names to IDs here but there isn't any reason why its would be better
+		// than allowing the agent to do it.
+		newToken.Roles = append(newToken.Roles, &api.ACLTokenRoleLink{Name: roleName})
+	}
+
+	for _, roleID := range c.roleIDs {
+		roleID, err := acl.GetRoleIDFromPartial(client, roleID)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error resolving role ID %s: %v", roleID, err))
+			return 1
+		}
+		newToken.Roles = append(newToken.Roles, &api.ACLTokenRoleLink{ID: roleID})
+	}
+
 	token, _, err := client.ACL().TokenCreate(newToken, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to create new token: %v", err))
 		return 1
 	}
 
-	acl.PrintToken(token, c.UI, c.showMeta)
+	acl.PrintToken(token, c.UI, c.showMeta, c.utc)
 	return 0
 }
 
@@ -106,10 +151,29 @@ Usage: consul acl token create [options]
   When creating a new token policies may be linked using either the -policy-id
   or the -policy-name options. When specifying policies by IDs you may use a
   unique prefix of the UUID as a shortcut for specifying the entire UUID.
+  Roles are linked the same way using the -role-id or -role-name options.
+  Service identities grant the privileges needed to register the named
+  service and its sidecar proxy, optionally limited to some datacenters.
 
   Create a new token:
 
           $ consul acl token create -description "Replication token"
                                             -policy-id b52fc3de-5
                                             -policy-name "acl-replication"
+
+  Create a new token linked to a role:
+
+          $ consul acl token create -description "Web token"
+                                            -role-name "web"
+
+  Create a new token for the "web" service in dc1:
+
+          $ consul acl token create -description "Web token"
+                                            -service-identity "web:dc1"
+
+  Create a new token that expires after one day:
+
+          $ consul acl token create -description "CI token"
+                                            -policy-name "deploy"
+                                            -expires-ttl 24h
 `
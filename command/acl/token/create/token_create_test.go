@@ -82,4 +82,65 @@ func TestTokenCreateCommand(t *testing.T) {
 		assert.Equal(code, 0)
 		assert.Empty(ui.ErrorWriter.String())
 	}
+
+	// create with role by name
+	{
+		role, _, err := client.ACL().RoleCreate(
+			&api.ACLRole{Name: "test-role"},
+			&api.WriteOptions{Token: "root"},
+		)
+		assert.NoError(err)
+
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-role-name=" + role.Name,
+			"-description=test token",
+		}
+
+		code := cmd.Run(args)
+		assert.Equal(code, 0)
+		assert.Empty(ui.ErrorWriter.String())
+		assert.Contains(ui.OutputWriter.String(), role.ID)
+	}
+
+	// create with service identity
+	{
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-service-identity=web:dc1,dc2",
+			"-description=test token",
+		}
+
+		code := cmd.Run(args)
+		assert.Equal(code, 0)
+		assert.Empty(ui.ErrorWriter.String())
+		assert.Contains(ui.OutputWriter.String(), "web (Datacenters: dc1, dc2)")
+	}
+
+	// create with an expiration TTL
+	{
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-policy-name=" + policy.Name,
+			"-expires-ttl=10m",
+			"-description=test token",
+		}
+
+		code := cmd.Run(args)
+		assert.Equal(code, 0)
+		assert.Empty(ui.ErrorWriter.String())
+		assert.Contains(ui.OutputWriter.String(), "Expires:")
+	}
 }
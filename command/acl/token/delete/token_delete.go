@@ -3,7 +3,9 @@ package tokendelete
 import (
 	"flag"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
@@ -22,6 +24,9 @@ type cmd struct {
 	help  string
 
 	tokenID string
+	labels  map[string]string
+	dryRun  bool
+	force   bool
 }
 
 func (c *cmd) init() {
@@ -29,7 +34,13 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.tokenID, "id", "", "The Accessor ID of the token to delete. "+
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
-	c.http = &flags.HTTPFlags{}
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Delete every token "+
+		"carrying this label, in the form key=value. May be specified multiple "+
+		"times, in which case all labels must match. Cannot be used with -id")
+	c.flags.BoolVar(&c.dryRun, "dry-run", false, "Only print the tokens that "+
+		"would be deleted with -label")
+	c.flags.BoolVar(&c.force, "force", false, "Do not ask for a confirmation "+
+		"when -label matches more than one token")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -41,8 +52,13 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	if c.tokenID == "" {
-		c.UI.Error(fmt.Sprintf("Must specify the -id parameter"))
+	if c.tokenID == "" && len(c.labels) == 0 {
+		c.UI.Error(fmt.Sprintf("Must specify either the -id or -label parameters"))
+		return 1
+	}
+
+	if c.tokenID != "" && len(c.labels) > 0 {
+		c.UI.Error(fmt.Sprintf("Cannot specify both the -id and -label parameters"))
 		return 1
 	}
 
@@ -52,6 +68,10 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if len(c.labels) > 0 {
+		return c.deleteByLabel(client)
+	}
+
 	tokenID, err := acl.GetTokenIDFromPartial(client, c.tokenID)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error determining token ID: %v", err))
@@ -67,6 +87,66 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) deleteByLabel(client *api.Client) int {
+	tokens, _, err := client.ACL().TokenList(&api.QueryOptions{Labels: c.labels})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the token list: %v", err))
+		return 1
+	}
+
+	// Never delete the token this command runs with, the remaining deletes
+	// would fail and the caller would lock themselves out.
+	self, _, err := client.ACL().TokenReadSelf(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the token in use: %v", err))
+		return 1
+	}
+
+	var matched []*api.ACLTokenListEntry
+	for _, token := range tokens {
+		if token.AccessorID == self.AccessorID {
+			c.UI.Warn(fmt.Sprintf("Skipping token %q, it is the token in use", token.AccessorID))
+			continue
+		}
+		matched = append(matched, token)
+	}
+
+	if len(matched) == 0 {
+		c.UI.Info(fmt.Sprintf("No tokens matched the given labels"))
+		return 0
+	}
+
+	if c.dryRun {
+		for _, token := range matched {
+			c.UI.Info(fmt.Sprintf("Would delete token %q (%s)", token.AccessorID, token.Description))
+		}
+		return 0
+	}
+
+	if len(matched) > 1 && !c.force {
+		confirm, err := c.UI.Ask(fmt.Sprintf("Delete %d tokens? (yes/no)", len(matched)))
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading the confirmation: %v", err))
+			return 1
+		}
+		if strings.TrimSpace(confirm) != "yes" {
+			c.UI.Info("Delete aborted")
+			return 1
+		}
+	}
+
+	result := 0
+	for _, token := range matched {
+		if _, err := client.ACL().TokenDelete(token.AccessorID, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Error deleting token %q: %v", token.AccessorID, err))
+			result = 1
+			continue
+		}
+		c.UI.Info(fmt.Sprintf("Token %q deleted successfully", token.AccessorID))
+	}
+	return result
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -88,4 +168,12 @@ Usage: consul acl token delete [options] -id TOKEN
       Delete by full ID:
 
           $ consul acl token delete -id b6b856da-5193-4e78-845a-7d61ca8371ba
+
+      Delete every token carrying a label:
+
+          $ consul acl token delete -label env=staging
+
+  Deleting more than one token by label asks for a confirmation unless -force
+  is given, -dry-run only lists the tokens. The token used to run the command
+  is never deleted by label.
 `
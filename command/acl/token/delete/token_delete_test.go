@@ -76,3 +76,114 @@ func TestTokenDeleteCommand(t *testing.T) {
 	)
 	assert.EqualError(err, "Unexpected response code: 403 (ACL not found)")
 }
+
+func TestTokenDeleteCommand_label(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	testDir := testutil.TempDir(t, "acl")
+	defer os.RemoveAll(testDir)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	client := a.Client()
+
+	var staging []string
+	for i := 0; i < 2; i++ {
+		token, _, err := client.ACL().TokenCreate(
+			&api.ACLToken{
+				Description: fmt.Sprintf("staging %d", i),
+				Labels:      map[string]string{"env": "staging"},
+			},
+			&api.WriteOptions{Token: "root"},
+		)
+		assert.NoError(err)
+		staging = append(staging, token.AccessorID)
+	}
+
+	prod, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description: "prod",
+			Labels:      map[string]string{"env": "prod"},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	// The token running the command carries the label too.
+	self, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description: "staging operator",
+			Policies:    []*api.ACLTokenPolicyLink{{ID: "00000000-0000-0000-0000-000000000001"}},
+			Labels:      map[string]string{"env": "staging"},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=" + self.SecretID,
+		"-label=env=staging",
+	}
+
+	// A dry run only lists the tokens.
+	code := cmd.Run(append(args, "-dry-run"))
+	assert.Equal(code, 0)
+	output := ui.OutputWriter.String()
+	for _, id := range staging {
+		assert.Contains(output, fmt.Sprintf("Would delete token %q", id))
+	}
+	assert.NotContains(output, prod.AccessorID)
+
+	// Anything but yes aborts.
+	ui = cli.NewMockUi()
+	ui.InputReader = strings.NewReader("no\n")
+	cmd = New(ui)
+	code = cmd.Run(args)
+	assert.Equal(code, 1)
+	assert.Contains(ui.OutputWriter.String(), "Delete aborted")
+	for _, id := range staging {
+		_, _, err = client.ACL().TokenRead(id, &api.QueryOptions{Token: "root"})
+		assert.NoError(err)
+	}
+
+	ui = cli.NewMockUi()
+	ui.InputReader = strings.NewReader("yes\n")
+	cmd = New(ui)
+	code = cmd.Run(args)
+	assert.Equal(code, 0)
+	assert.Contains(ui.OutputWriter.String(), "Delete 2 tokens? (yes/no)")
+	assert.Equal(fmt.Sprintf("Skipping token %q, it is the token in use\n", self.AccessorID), ui.ErrorWriter.String())
+
+	_, _, err = client.ACL().TokenRead(self.AccessorID, &api.QueryOptions{Token: "root"})
+	assert.NoError(err)
+
+	output = ui.OutputWriter.String()
+	for _, id := range staging {
+		assert.Contains(output, id)
+
+		_, _, err = client.ACL().TokenRead(id, &api.QueryOptions{Token: "root"})
+		assert.EqualError(err, "Unexpected response code: 403 (ACL not found)")
+	}
+	assert.NotContains(output, prod.AccessorID)
+
+	token, _, err := client.ACL().TokenRead(prod.AccessorID, &api.QueryOptions{Token: "root"})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"env": "prod"}, token.Labels)
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
@@ -21,13 +22,31 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	showMeta bool
+	labels     map[string]string
+	policyID   string
+	policyName string
+	roleID     string
+	roleName   string
+	showMeta   bool
+	utc        bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that token metadata such "+
 		"as the content hash and Raft indices should be shown for each entry")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of the local time zone")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Only list tokens "+
+		"carrying this label, in the form key=value. May be specified multiple "+
+		"times, in which case all labels must match")
+	c.flags.StringVar(&c.policyID, "policy-id", "", "Only list tokens linked "+
+		"with the policy with this ID. A unique prefix of the ID may be used")
+	c.flags.StringVar(&c.policyName, "policy-name", "", "Only list tokens linked "+
+		"with the policy with this name")
+	c.flags.StringVar(&c.roleID, "role-id", "", "Only list tokens linked "+
+		"with the role with this ID. A unique prefix of the ID may be used")
+	c.flags.StringVar(&c.roleName, "role-name", "", "Only list tokens linked "+
+		"with the role with this name")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -39,13 +58,40 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	policySet := c.policyID != "" || c.policyName != ""
+	roleSet := c.roleID != "" || c.roleName != ""
+	if (c.policyID != "" && c.policyName != "") || (c.roleID != "" && c.roleName != "") {
+		c.UI.Error("Only one of the ID and name of a policy or role may be given")
+		return 1
+	}
+	if policySet && roleSet {
+		c.UI.Error("Cannot filter by both a policy and a role")
+		return 1
+	}
+
 	client, err := c.http.APIClient()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
 		return 1
 	}
 
-	tokens, _, err := client.ACL().TokenList(nil)
+	var filter api.ACLTokenFilterOptions
+	switch {
+	case c.policyID != "":
+		filter.Policy, err = acl.GetPolicyIDFromPartial(client, c.policyID)
+	case c.policyName != "":
+		filter.Policy, err = acl.GetPolicyIDByName(client, c.policyName)
+	case c.roleID != "":
+		filter.Role, err = acl.GetRoleIDFromPartial(client, c.roleID)
+	case c.roleName != "":
+		filter.Role, err = acl.GetRoleIDByName(client, c.roleName)
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error determining the filter: %v", err))
+		return 1
+	}
+
+	tokens, _, err := client.ACL().TokenListFiltered(filter, &api.QueryOptions{Labels: c.labels})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to retrieve the token list: %v", err))
 		return 1
@@ -58,7 +104,7 @@ func (c *cmd) Run(args []string) int {
 		} else {
 			c.UI.Info("")
 		}
-		acl.PrintTokenListEntry(token, c.UI, c.showMeta)
+		acl.PrintTokenListEntry(token, c.UI, c.showMeta, c.utc)
 	}
 
 	return 0
@@ -79,4 +125,16 @@ Usage: consul acl token list [options]
   List all the ALC tokens
 
           $ consul acl token list
+
+  List only the tokens carrying a label:
+
+          $ consul acl token list -label team=payments
+
+  List only the tokens linked with a policy:
+
+          $ consul acl token list -policy-name node-read
+
+  List only the tokens linked with a role:
+
+          $ consul acl token list -role-id 0a8c3e
 `
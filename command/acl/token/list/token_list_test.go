@@ -78,3 +78,111 @@ func TestTokenListCommand(t *testing.T) {
 		assert.Contains(output, v)
 	}
 }
+
+func TestTokenListCommand_filter(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "test-policy"},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	linked, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description: "linked token",
+			Policies:    []*api.ACLTokenPolicyLink{&api.ACLTokenPolicyLink{ID: policy.ID}},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	role, _, err := client.ACL().RoleCreate(
+		&api.ACLRole{
+			Name:     "test-role",
+			Policies: []*api.ACLRolePolicyLink{&api.ACLRolePolicyLink{ID: policy.ID}},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	roleLinked, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description: "role linked token",
+			Roles:       []*api.ACLTokenRoleLink{&api.ACLTokenRoleLink{ID: role.ID}},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	unlinked, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "unlinked token"},
+		&api.WriteOptions{Token: "root"},
+	)
+	assert.NoError(err)
+
+	for _, filter := range []string{"-policy-name=test-policy", "-policy-id=" + policy.ID[:8]} {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			filter,
+		})
+		assert.Equal(code, 0)
+		assert.Empty(ui.ErrorWriter.String())
+		output := ui.OutputWriter.String()
+		assert.Contains(output, linked.AccessorID)
+		assert.NotContains(output, roleLinked.AccessorID)
+		assert.NotContains(output, unlinked.AccessorID)
+	}
+
+	for _, filter := range []string{"-role-name=test-role", "-role-id=" + role.ID[:8]} {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			filter,
+		})
+		assert.Equal(code, 0)
+		assert.Empty(ui.ErrorWriter.String())
+		output := ui.OutputWriter.String()
+		assert.Contains(output, roleLinked.AccessorID)
+		assert.NotContains(output, linked.AccessorID)
+		assert.NotContains(output, unlinked.AccessorID)
+	}
+
+	// filtering by a policy and a role at once is rejected
+	{
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-policy-name=test-policy",
+			"-role-name=test-role",
+		})
+		assert.Equal(code, 1)
+		assert.Contains(ui.ErrorWriter.String(), "Cannot filter by both a policy and a role")
+	}
+}
@@ -1,6 +1,7 @@
 package tokenread
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 
@@ -25,14 +26,32 @@ type cmd struct {
 	tokenID  string
 	self     bool
 	showMeta bool
+	utc      bool
+	expanded bool
+	format   string
+}
+
+// expandedToken is the JSON output of -expanded, the token along with its
+// effective grants.
+type expandedToken struct {
+	*api.ACLToken
+	EffectiveGrants *acl.TokenGrants
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that token metadata such "+
 		"as the content hash and Raft indices should be shown for each entry")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of the local time zone")
+	c.flags.BoolVar(&c.expanded, "expanded", false, "Indicates that the effective "+
+		"grants of the token should be resolved and shown, including the rules of "+
+		"every linked policy")
+	c.flags.StringVar(&c.format, "format", "pretty", "Output format of the token, "+
+		"either \"pretty\" or \"json\". With -expanded the JSON output includes "+
+		"the graph of effective grants")
 	c.flags.BoolVar(&c.self, "self", false, "Indicates that the current HTTP token "+
-		"should be read by secret ID instead of expecting a -id option")
+		"should be read by secret ID instead of expecting a -id option. This does "+
+		"not require acl:read")
 	c.flags.StringVar(&c.tokenID, "id", "", "The Accessor ID of the token to read. "+
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
@@ -48,7 +67,15 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	if c.tokenID == "" && !c.self {
-		c.UI.Error(fmt.Sprintf("Must specify the -id parameter"))
+		c.UI.Error(fmt.Sprintf("Must specify either the -id or -self parameters"))
+		return 1
+	}
+	if c.tokenID != "" && c.self {
+		c.UI.Error(fmt.Sprintf("Cannot specify both the -id and -self parameters"))
+		return 1
+	}
+	if c.format != "pretty" && c.format != "json" {
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be \"pretty\" or \"json\"", c.format))
 		return 1
 	}
 
@@ -79,7 +106,67 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
-	acl.PrintToken(token, c.UI, c.showMeta)
+	if !c.expanded {
+		if c.format == "json" {
+			return c.outputJSON(token)
+		}
+		acl.PrintToken(token, c.UI, c.showMeta, c.utc)
+		return 0
+	}
+
+	roles := make(map[string]*api.ACLRole)
+	policyIDs := make([]string, 0, len(token.Policies))
+	for _, link := range token.Policies {
+		policyIDs = append(policyIDs, link.ID)
+	}
+	for _, link := range token.Roles {
+		role, _, err := client.ACL().RoleRead(link.ID, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading role %q: %v", link.ID, err))
+			return 1
+		}
+		if role == nil {
+			// The role was deleted since the token was read.
+			c.UI.Warn(fmt.Sprintf("Role not found with ID %q", link.ID))
+			continue
+		}
+		roles[link.ID] = role
+		for _, policyLink := range role.Policies {
+			policyIDs = append(policyIDs, policyLink.ID)
+		}
+	}
+
+	policies := make(map[string]*api.ACLPolicy)
+	for _, policyID := range policyIDs {
+		if _, ok := policies[policyID]; ok {
+			continue
+		}
+		policy, _, err := client.ACL().PolicyRead(policyID, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading policy %q: %v", policyID, err))
+			return 1
+		}
+		policies[policyID] = policy
+	}
+
+	if c.format == "json" {
+		return c.outputJSON(&expandedToken{
+			ACLToken:        token,
+			EffectiveGrants: acl.NewTokenGrants(token, roles, policies),
+		})
+	}
+	acl.PrintToken(token, c.UI, c.showMeta, c.utc)
+	acl.PrintTokenGrants(token, roles, policies, c.UI)
+	return 0
+}
+
+func (c *cmd) outputJSON(v interface{}) int {
+	out, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to encode the token: %v", err))
+		return 1
+	}
+	c.UI.Output(string(out))
 	return 0
 }
 
@@ -93,7 +180,7 @@ func (c *cmd) Help() string {
 
 const synopsis = "Read an ACL Token"
 const help = `
-Usage: consul acl token read [options] -id TOKENID
+Usage: consul acl token read [options] [-id TOKENID | -self]
 
   This command will retrieve and print out the details of
   a single token.
@@ -105,4 +192,16 @@ Usage: consul acl token read [options] -id TOKENID
   Using the full ID:
 
           $ consul acl token read -id 4be56c77-8244-4c7d-b08c-667b8c71baed
+
+  Reading the token used to make the request, without needing acl:read:
+
+          $ consul acl token read -self
+
+  Showing the rules of every linked policy:
+
+          $ consul acl token read -id 4be56c77 -expanded
+
+  Emitting the token and its effective grants as JSON:
+
+          $ consul acl token read -id 4be56c77 -expanded -format=json
 `
@@ -1,18 +1,22 @@
 package tokenread
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/logger"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTokenReadCommand_noTabs(t *testing.T) {
@@ -71,3 +75,145 @@ func TestTokenReadCommand(t *testing.T) {
 	assert.Contains(output, token.AccessorID)
 	assert.Contains(output, token.SecretID)
 }
+
+func TestTokenReadCommand_self(t *testing.T) {
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		default_policy = "deny"
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	// The token gets no acl:read, reading itself must still work.
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "kv-read", Rules: `key_prefix "" { policy = "read" }`},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description:   "workload",
+			Policies:      []*api.ACLTokenPolicyLink{{ID: policy.ID}},
+			ExpirationTTL: time.Hour,
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=" + token.SecretID,
+		"-self",
+	})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, token.AccessorID)
+	require.Contains(t, output, "workload")
+	require.Contains(t, output, fmt.Sprintf("%s - kv-read", policy.ID))
+	require.Contains(t, output, "Expires:")
+
+	t.Run("id and self", func(t *testing.T) {
+		require := require.New(t)
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + token.SecretID,
+			"-self",
+			"-id=" + token.AccessorID,
+		})
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), "Cannot specify both")
+	})
+}
+
+func TestTokenReadCommand_expanded(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	client := a.Client()
+
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "test-policy", Rules: `node_prefix "" { policy = "read" }`},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description:       "test",
+			Policies:          []*api.ACLTokenPolicyLink{&api.ACLTokenPolicyLink{ID: policy.ID}},
+			ServiceIdentities: []*api.ACLServiceIdentity{{ServiceName: "web"}},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(err)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-id=" + token.AccessorID,
+		"-expanded",
+	}
+
+	code := cmd.Run(args)
+	require.Equal(0, code)
+	require.Empty(ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(output, "Effective Grants:")
+	require.Contains(output, fmt.Sprintf(`Policy "test-policy" (%s)`, policy.ID))
+	require.Contains(output, "Datacenters: all")
+	require.Contains(output, `         node_prefix "" { policy = "read" }`)
+	require.Contains(output, `Service Identity "web"`)
+	require.Contains(output, `         service "web-sidecar-proxy" {`)
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run(append(args, "-format=json"))
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	var out struct {
+		AccessorID      string
+		EffectiveGrants acl.TokenGrants
+	}
+	require.NoError(json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.Equal(token.AccessorID, out.AccessorID)
+	require.Len(out.EffectiveGrants.Policies, 1)
+	require.Equal(policy.ID, out.EffectiveGrants.Policies[0].ID)
+	require.Equal(policy.Rules, out.EffectiveGrants.Policies[0].Rules)
+	require.False(out.EffectiveGrants.Policies[0].NotFound)
+	require.Len(out.EffectiveGrants.ServiceIdentities, 1)
+	require.Equal("web", out.EffectiveGrants.ServiceIdentities[0].ServiceName)
+	require.Contains(out.EffectiveGrants.ServiceIdentities[0].Rules, `service "web" {`)
+}
@@ -22,29 +22,52 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	tokenID       string
-	policyIDs     []string
-	policyNames   []string
-	description   string
-	mergePolicies bool
-	showMeta      bool
-	upgradeLegacy bool
+	tokenID            string
+	policyIDs          []string
+	policyNames        []string
+	roleIDs            []string
+	roleNames          []string
+	serviceIdents      []string
+	description        string
+	labels             map[string]string
+	mergePolicies      bool
+	mergeRoles         bool
+	mergeServiceIdents bool
+	showMeta           bool
+	utc                bool
+	upgradeLegacy      bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that token metadata such "+
 		"as the content hash and raft indices should be shown for each entry")
+	c.flags.BoolVar(&c.utc, "utc", false, "Render timestamps in UTC instead of the local time zone")
 	c.flags.BoolVar(&c.mergePolicies, "merge-policies", false, "Merge the new policies "+
 		"with the existing policies")
+	c.flags.BoolVar(&c.mergeRoles, "merge-roles", false, "Merge the new roles "+
+		"with the existing roles")
+	c.flags.BoolVar(&c.mergeServiceIdents, "merge-service-identities", false, "Merge "+
+		"the new service identities with the existing service identities")
 	c.flags.StringVar(&c.tokenID, "id", "", "The Accessor ID of the token to read. "+
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
 	c.flags.StringVar(&c.description, "description", "", "A description of the token")
+	c.flags.Var((*flags.FlagMapValue)(&c.labels), "label", "Label to set on the "+
+		"token, in the form key=value. Labels are merged with the existing labels "+
+		"and an empty value removes the label. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.policyIDs), "policy-id", "ID of a "+
 		"policy to use for this token. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.policyNames), "policy-name", "Name of a "+
 		"policy to use for this token. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.roleIDs), "role-id", "ID of a "+
+		"role to use for this token. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.roleNames), "role-name", "Name of a "+
+		"role to use for this token. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.serviceIdents), "service-identity", "Name of a "+
+		"service identity to use for this token, optionally followed by a colon and a "+
+		"comma separated list of datacenters, e.g. web:dc1,dc2. May be specified "+
+		"multiple times")
 	c.flags.BoolVar(&c.upgradeLegacy, "upgrade-legacy", false, "Add new polices "+
 		"to a legacy token replacing all existing rules. This will cause the legacy "+
 		"token to behave exactly like a new token but keep the same Secret.\n"+
@@ -67,6 +90,12 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	serviceIdents, err := acl.ExtractServiceIdentities(c.serviceIdents)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	client, err := c.http.APIClient()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
@@ -107,6 +136,10 @@ func (c *cmd) Run(args []string) int {
 		token.Description = c.description
 	}
 
+	if len(c.labels) > 0 {
+		token.Labels = acl.MergeLabels(token.Labels, c.labels)
+	}
+
 	if c.mergePolicies {
 		for _, policyName := range c.policyNames {
 			found := false
@@ -162,6 +195,67 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
+	if c.mergeRoles {
+		for _, roleName := range c.roleNames {
+			found := false
+			for _, link := range token.Roles {
+				if link.Name == roleName {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				// We could resolve names to IDs here but there isn't any reason why its would be better
+				// than allowing the agent to do it.
+				token.Roles = append(token.Roles, &api.ACLTokenRoleLink{Name: roleName})
+			}
+		}
+
+		for _, roleID := range c.roleIDs {
+			roleID, err := acl.GetRoleIDFromPartial(client, roleID)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error resolving role ID %s: %v", roleID, err))
+				return 1
+			}
+			found := false
+
+			for _, link := range token.Roles {
+				if link.ID == roleID {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				token.Roles = append(token.Roles, &api.ACLTokenRoleLink{ID: roleID})
+			}
+		}
+	} else {
+		token.Roles = nil
+
+		for _, roleName := range c.roleNames {
+			// We could resolve names to IDs here but there isn't any reason why its would be better
+			// than allowing the agent to do it.
+			token.Roles = append(token.Roles, &api.ACLTokenRoleLink{Name: roleName})
+		}
+
+		for _, roleID := range c.roleIDs {
+			roleID, err := acl.GetRoleIDFromPartial(client, roleID)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error resolving role ID %s: %v", roleID, err))
+				return 1
+			}
+			token.Roles = append(token.Roles, &api.ACLTokenRoleLink{ID: roleID})
+		}
+	}
+
+	if c.mergeServiceIdents {
+		token.ServiceIdentities = acl.MergeServiceIdentities(token.ServiceIdentities, serviceIdents)
+	} else {
+		token.ServiceIdentities = serviceIdents
+	}
+
 	token, _, err = client.ACL().TokenUpdate(token, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to update token %s: %v", tokenID, err))
@@ -169,7 +263,7 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	c.UI.Info("Token updated successfully.")
-	acl.PrintToken(token, c.UI, c.showMeta)
+	acl.PrintToken(token, c.UI, c.showMeta, c.utc)
 	return 0
 }
 
@@ -190,7 +284,15 @@ Usage: consul acl token update [options]
 
     Update a token description and take the policies from the existing token:
 
-        $ consul acl token update -id abcd -description "replication" -merge-policies
+        $ consul acl token update -id abcd -description "replication" -merge-policies -merge-roles -merge-service-identities
+
+    Add a role to a token while keeping its existing roles:
+
+        $ consul acl token update -id abcd -role-name "web" -merge-roles
+
+    Add a service identity to a token while keeping its existing ones:
+
+        $ consul acl token update -id abcd -service-identity "web:dc1" -merge-service-identities
 
       Update all editable fields of the token:
 
@@ -0,0 +1,256 @@
+package tokenupgradelegacy
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	policyPrefix string
+	dryRun       bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.policyPrefix, "policy-prefix", "legacy-", "The prefix "+
+		"of the names of the policies created from the legacy rules. The name is "+
+		"completed with a hash of the translated rules")
+	c.flags.BoolVar(&c.dryRun, "dry-run", false, "Only print the policies that "+
+		"would be created and the tokens that would be upgraded")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	// Legacy tokens are identified by their type, which only the legacy API
+	// exposes. Management tokens and client tokens without rules have nothing
+	// to translate but are still legacy tokens.
+	entries, _, err := client.ACL().List(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the legacy token list: %v", err))
+		return 1
+	}
+
+	policies, _, err := client.ACL().PolicyList(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the policy list: %v", err))
+		return 1
+	}
+	existing := make(map[string]string)
+	for _, policy := range policies {
+		existing[policy.Name] = policy.ID
+	}
+
+	// Tokens with the same rules share a policy. The translations are cached
+	// by the legacy rules and the policies by their name, which is derived
+	// from the translated rules.
+	translations := make(map[string]string)
+	policyIDs := make(map[string]string)
+
+	upgraded, created, failed, pending := 0, 0, 0, 0
+	for _, entry := range entries {
+		// The legacy API only exposes the secret, which is used to read the
+		// rest of the token.
+		token, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: entry.ID})
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading legacy token %q: %v", entry.Name, err))
+			failed++
+			continue
+		}
+		if token.AccessorID == structs.ACLTokenAnonymousID {
+			continue
+		}
+		if token.AccessorID == "" {
+			// The leader assigns accessor IDs to tokens created before 1.4 in the
+			// background, they can't be addressed until then.
+			pending++
+			continue
+		}
+
+		var link *api.ACLTokenPolicyLink
+		var grant string
+		switch {
+		case entry.Type == api.ACLManagementType:
+			grant = "policy global-management"
+			if !hasPolicy(token, structs.ACLPolicyGlobalManagementID) {
+				link = &api.ACLTokenPolicyLink{ID: structs.ACLPolicyGlobalManagementID}
+			}
+
+		case token.Rules != "":
+			rules, ok := translations[token.Rules]
+			if !ok {
+				rules, err = client.ACL().RulesTranslate(strings.NewReader(token.Rules))
+				if err != nil {
+					c.UI.Error(fmt.Sprintf("Cannot translate the rules of token %q: %v", token.AccessorID, err))
+					failed++
+					continue
+				}
+				translations[token.Rules] = rules
+			}
+
+			sum := sha256.Sum256([]byte(rules))
+			name := fmt.Sprintf("%s%x", c.policyPrefix, sum[:8])
+			policyID, ok := policyIDs[name]
+			if !ok {
+				policyID, err = c.policyFor(client, name, rules, existing[name])
+				if err != nil {
+					c.UI.Error(fmt.Sprintf("Cannot upgrade token %q: %v", token.AccessorID, err))
+					failed++
+					continue
+				}
+				if existing[name] == "" {
+					created++
+				}
+				policyIDs[name] = policyID
+			}
+			link = &api.ACLTokenPolicyLink{ID: policyID}
+			grant = "policy " + name
+
+		default:
+			// A client token without rules only has the default policy, as
+			// does a token without any policy.
+			grant = "no policy"
+		}
+
+		if c.dryRun {
+			c.UI.Info(fmt.Sprintf("Would upgrade token %s (%s) with %s",
+				token.AccessorID, token.Description, grant))
+			upgraded++
+			continue
+		}
+
+		// Updating the token through the current API clears its rules and
+		// type, making it a regular token that keeps its secret.
+		token.Rules = ""
+		if link != nil {
+			token.Policies = append(token.Policies, link)
+		}
+		if _, _, err := client.ACL().TokenUpdate(token, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to upgrade token %q: %v", token.AccessorID, err))
+			failed++
+			continue
+		}
+		c.UI.Info(fmt.Sprintf("Upgraded token %s (%s) with %s",
+			token.AccessorID, token.Description, grant))
+		upgraded++
+	}
+
+	if c.dryRun {
+		c.UI.Info(fmt.Sprintf("Would upgrade %d legacy tokens, creating %d policies", upgraded, created))
+	} else {
+		c.UI.Info(fmt.Sprintf("Upgraded %d legacy tokens, creating %d policies", upgraded, created))
+	}
+	if pending > 0 {
+		c.UI.Warn(fmt.Sprintf("%d legacy tokens have no accessor ID yet, run this "+
+			"command again once the leader has assigned them", pending))
+	}
+	if failed > 0 {
+		c.UI.Error(fmt.Sprintf("%d legacy tokens could not be upgraded and have "+
+			"to be upgraded manually", failed))
+	}
+	if pending > 0 || failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func hasPolicy(token *api.ACLToken, id string) bool {
+	for _, link := range token.Policies {
+		if link.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// policyFor returns the ID of the policy with the given name, creating it from
+// the rules unless it already exists. An existing policy is only reused when
+// it holds the same rules.
+func (c *cmd) policyFor(client *api.Client, name, rules, existingID string) (string, error) {
+	if existingID != "" {
+		policy, _, err := client.ACL().PolicyRead(existingID, nil)
+		if err != nil {
+			return "", fmt.Errorf("Error reading policy %q: %v", name, err)
+		}
+		if policy.Rules != rules {
+			return "", fmt.Errorf("Policy %q already exists with different rules", name)
+		}
+		return policy.ID, nil
+	}
+
+	if c.dryRun {
+		c.UI.Info(fmt.Sprintf("Would create policy %s:\n%s", name, rules))
+		return "", nil
+	}
+
+	policy, _, err := client.ACL().PolicyCreate(&api.ACLPolicy{
+		Name:        name,
+		Description: "Rules of legacy tokens upgraded by consul acl token upgrade-legacy",
+		Rules:       rules,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create policy %q: %v", name, err)
+	}
+	c.UI.Info(fmt.Sprintf("Created policy %s (%s)", name, policy.ID))
+	return policy.ID, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const synopsis = "Upgrade all legacy ACL tokens"
+const help = `
+Usage: consul acl token upgrade-legacy [options]
+
+  Upgrades every legacy token to a token linked to a policy. The rules of
+  each client token are translated to the current syntax and put in a policy,
+  and tokens with the same rules share a policy. Management tokens, including
+  the bootstrap and master tokens, are linked to the builtin
+  global-management policy. Client tokens without rules keep only the default
+  policy. The tokens keep their accessor and secret IDs. Tokens that cannot
+  be upgraded are reported and left untouched, and the command then exits
+  with an error.
+
+  Showing what would be done:
+
+      $ consul acl token upgrade-legacy -dry-run
+
+  Upgrading the tokens:
+
+      $ consul acl token upgrade-legacy
+`
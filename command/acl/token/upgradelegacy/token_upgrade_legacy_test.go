@@ -0,0 +1,156 @@
+package tokenupgradelegacy
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/logger"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenUpgradeLegacyCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenUpgradeLegacyCommand(t *testing.T) {
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, t.Name(), `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			master = "root"
+		}
+	}`)
+
+	a.Agent.LogWriter = logger.NewLogWriter(512)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	qopts := &api.QueryOptions{Token: "root"}
+
+	// createLegacy creates a legacy token and waits for the leader to assign
+	// it an accessor ID.
+	createLegacy := func(name, tokenType, rules string) *api.ACLToken {
+		secret, _, err := client.ACL().Create(&api.ACLEntry{
+			Name:  name,
+			Type:  tokenType,
+			Rules: rules,
+		}, &api.WriteOptions{Token: "root"})
+		require.NoError(t, err)
+
+		var token *api.ACLToken
+		retry.Run(t, func(r *retry.R) {
+			token, _, err = client.ACL().TokenReadSelf(&api.QueryOptions{Token: secret})
+			r.Check(err)
+			if token.AccessorID == "" {
+				r.Fatal("no accessor ID yet")
+			}
+		})
+		return token
+	}
+
+	run := func(args ...string) (int, *cli.MockUi) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(append([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+		}, args...))
+		return code, ui
+	}
+
+	kv1 := createLegacy("kv 1", "client", `key "foo" { policy = "read" }`)
+	kv2 := createLegacy("kv 2", "client", `key "foo" { policy = "read" }`)
+	web := createLegacy("web", "client", `service "web" { policy = "write" }`)
+	mgmt := createLegacy("mgmt", "management", "")
+	empty := createLegacy("empty", "client", "")
+
+	// The master token is a legacy management token as well.
+	code, ui := run("-dry-run")
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Would upgrade 6 legacy tokens, creating 2 policies")
+	require.Contains(t, ui.OutputWriter.String(), fmt.Sprintf("Would upgrade token %s (mgmt) with policy global-management", mgmt.AccessorID))
+
+	token, _, err := client.ACL().TokenRead(kv1.AccessorID, qopts)
+	require.NoError(t, err)
+	require.NotEmpty(t, token.Rules)
+
+	code, ui = run()
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Upgraded 6 legacy tokens, creating 2 policies")
+
+	read := func(legacy *api.ACLToken) *api.ACLToken {
+		token, _, err := client.ACL().TokenRead(legacy.AccessorID, qopts)
+		require.NoError(t, err)
+		require.Empty(t, token.Rules)
+		require.Equal(t, legacy.SecretID, token.SecretID)
+		require.Len(t, token.Policies, 1)
+		return token
+	}
+
+	kv1Token, kv2Token, webToken := read(kv1), read(kv2), read(web)
+	require.Equal(t, kv1Token.Policies[0].ID, kv2Token.Policies[0].ID)
+	require.NotEqual(t, kv1Token.Policies[0].ID, webToken.Policies[0].ID)
+
+	policy, _, err := client.ACL().PolicyRead(kv1Token.Policies[0].ID, qopts)
+	require.NoError(t, err)
+	require.Contains(t, policy.Rules, `key_prefix "foo"`)
+	require.True(t, strings.HasPrefix(policy.Name, "legacy-"))
+
+	mgmtToken := read(mgmt)
+	require.Equal(t, "00000000-0000-0000-0000-000000000001", mgmtToken.Policies[0].ID)
+
+	emptyToken, _, err := client.ACL().TokenRead(empty.AccessorID, qopts)
+	require.NoError(t, err)
+	require.Empty(t, emptyToken.Policies)
+
+	// No legacy tokens are left.
+	entries, _, err := client.ACL().List(qopts)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	code, ui = run()
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Upgraded 0 legacy tokens, creating 0 policies")
+
+	t.Run("name taken", func(t *testing.T) {
+		require := require.New(t)
+		node := createLegacy("node", "client", `node "foo" { policy = "read" }`)
+
+		// The translate endpoint takes no query options, so it needs a client
+		// with the token set.
+		rootClient, err := api.NewClient(&api.Config{Address: a.HTTPAddr(), Token: "root"})
+		require.NoError(err)
+		rules, err := rootClient.ACL().RulesTranslate(strings.NewReader(node.Rules))
+		require.NoError(err)
+		sum := sha256.Sum256([]byte(rules))
+		_, _, err = client.ACL().PolicyCreate(&api.ACLPolicy{
+			Name:  fmt.Sprintf("legacy-%x", sum[:8]),
+			Rules: `node_prefix "" { policy = "write" }`,
+		}, &api.WriteOptions{Token: "root"})
+		require.NoError(err)
+
+		code, ui := run()
+		require.Equal(1, code)
+		require.Contains(ui.ErrorWriter.String(), "already exists with different rules")
+		require.Contains(ui.ErrorWriter.String(), "1 legacy tokens could not be upgraded")
+
+		token, _, err := client.ACL().TokenRead(node.AccessorID, qopts)
+		require.NoError(err)
+		require.NotEmpty(token.Rules)
+	})
+}
@@ -4,13 +4,24 @@ import (
 	"github.com/hashicorp/consul/command/acl"
 	aclagent "github.com/hashicorp/consul/command/acl/agenttokens"
 	aclbootstrap "github.com/hashicorp/consul/command/acl/bootstrap"
+	aclbrowse "github.com/hashicorp/consul/command/acl/browse"
+	aclexport "github.com/hashicorp/consul/command/acl/export"
+	aclimport "github.com/hashicorp/consul/command/acl/import"
 	aclpolicy "github.com/hashicorp/consul/command/acl/policy"
 	aclpcreate "github.com/hashicorp/consul/command/acl/policy/create"
 	aclpdelete "github.com/hashicorp/consul/command/acl/policy/delete"
 	aclplist "github.com/hashicorp/consul/command/acl/policy/list"
 	aclpread "github.com/hashicorp/consul/command/acl/policy/read"
 	aclpupdate "github.com/hashicorp/consul/command/acl/policy/update"
+	aclpvalidate "github.com/hashicorp/consul/command/acl/policy/validate"
+	aclrole "github.com/hashicorp/consul/command/acl/role"
+	aclrcreate "github.com/hashicorp/consul/command/acl/role/create"
+	aclrdelete "github.com/hashicorp/consul/command/acl/role/delete"
+	aclrlist "github.com/hashicorp/consul/command/acl/role/list"
+	aclrread "github.com/hashicorp/consul/command/acl/role/read"
+	aclrupdate "github.com/hashicorp/consul/command/acl/role/update"
 	aclrules "github.com/hashicorp/consul/command/acl/rules"
+	acltest "github.com/hashicorp/consul/command/acl/test"
 	acltoken "github.com/hashicorp/consul/command/acl/token"
 	acltclone "github.com/hashicorp/consul/command/acl/token/clone"
 	acltcreate "github.com/hashicorp/consul/command/acl/token/create"
@@ -18,6 +29,7 @@ import (
 	acltlist "github.com/hashicorp/consul/command/acl/token/list"
 	acltread "github.com/hashicorp/consul/command/acl/token/read"
 	acltupdate "github.com/hashicorp/consul/command/acl/token/update"
+	acltupgrade "github.com/hashicorp/consul/command/acl/token/upgradelegacy"
 	"github.com/hashicorp/consul/command/agent"
 	"github.com/hashicorp/consul/command/catalog"
 	catlistdc "github.com/hashicorp/consul/command/catalog/list/dc"
@@ -91,12 +103,23 @@ func init() {
 
 	Register("acl", func(cli.Ui) (cli.Command, error) { return acl.New(), nil })
 	Register("acl bootstrap", func(ui cli.Ui) (cli.Command, error) { return aclbootstrap.New(ui), nil })
+	Register("acl browse", func(ui cli.Ui) (cli.Command, error) { return aclbrowse.New(ui), nil })
+	Register("acl export", func(ui cli.Ui) (cli.Command, error) { return aclexport.New(ui), nil })
+	Register("acl import", func(ui cli.Ui) (cli.Command, error) { return aclimport.New(ui), nil })
 	Register("acl policy", func(cli.Ui) (cli.Command, error) { return aclpolicy.New(), nil })
 	Register("acl policy create", func(ui cli.Ui) (cli.Command, error) { return aclpcreate.New(ui), nil })
 	Register("acl policy list", func(ui cli.Ui) (cli.Command, error) { return aclplist.New(ui), nil })
 	Register("acl policy read", func(ui cli.Ui) (cli.Command, error) { return aclpread.New(ui), nil })
 	Register("acl policy update", func(ui cli.Ui) (cli.Command, error) { return aclpupdate.New(ui), nil })
 	Register("acl policy delete", func(ui cli.Ui) (cli.Command, error) { return aclpdelete.New(ui), nil })
+	Register("acl policy validate", func(ui cli.Ui) (cli.Command, error) { return aclpvalidate.New(ui), nil })
+	Register("acl role", func(cli.Ui) (cli.Command, error) { return aclrole.New(), nil })
+	Register("acl role create", func(ui cli.Ui) (cli.Command, error) { return aclrcreate.New(ui), nil })
+	Register("acl role list", func(ui cli.Ui) (cli.Command, error) { return aclrlist.New(ui), nil })
+	Register("acl role read", func(ui cli.Ui) (cli.Command, error) { return aclrread.New(ui), nil })
+	Register("acl role update", func(ui cli.Ui) (cli.Command, error) { return aclrupdate.New(ui), nil })
+	Register("acl role delete", func(ui cli.Ui) (cli.Command, error) { return aclrdelete.New(ui), nil })
+	Register("acl test", func(ui cli.Ui) (cli.Command, error) { return acltest.New(ui), nil })
 	Register("acl translate-rules", func(ui cli.Ui) (cli.Command, error) { return aclrules.New(ui), nil })
 	Register("acl set-agent-token", func(ui cli.Ui) (cli.Command, error) { return aclagent.New(ui), nil })
 	Register("acl token", func(cli.Ui) (cli.Command, error) { return acltoken.New(), nil })
@@ -106,6 +129,7 @@ func init() {
 	Register("acl token read", func(ui cli.Ui) (cli.Command, error) { return acltread.New(ui), nil })
 	Register("acl token update", func(ui cli.Ui) (cli.Command, error) { return acltupdate.New(ui), nil })
 	Register("acl token delete", func(ui cli.Ui) (cli.Command, error) { return acltdelete.New(ui), nil })
+	Register("acl token upgrade-legacy", func(ui cli.Ui) (cli.Command, error) { return acltupgrade.New(ui), nil })
 	Register("agent", func(ui cli.Ui) (cli.Command, error) {
 		return agent.New(ui, rev, ver, verPre, verHuman, make(chan struct{})), nil
 	})
@@ -4,11 +4,18 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 )
 
 func LoadDataSource(data string, testStdin io.Reader) (string, error) {
+	return LoadDataSourceMax(data, testStdin, 0)
+}
+
+// LoadDataSourceMax is like LoadDataSource but refuses values larger than max
+// bytes. Files and stdin are read through a limited reader so an oversized
+// input is rejected without being buffered in full. A max of zero disables
+// the limit.
+func LoadDataSourceMax(data string, testStdin io.Reader, max int64) (string, error) {
 	var stdin io.Reader = os.Stdin
 	if testStdin != nil {
 		stdin = testStdin
@@ -21,22 +28,60 @@ func LoadDataSource(data string, testStdin io.Reader) (string, error) {
 
 	switch data[0] {
 	case '@':
-		data, err := ioutil.ReadFile(data[1:])
+		f, err := os.Open(data[1:])
+		if err != nil {
+			return "", fmt.Errorf("Failed to read file: %s", err)
+		}
+		defer f.Close()
+
+		if max > 0 {
+			if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() && fi.Size() > max {
+				return "", fmt.Errorf("File %q is %d bytes which exceeds the %d byte limit", data[1:], fi.Size(), max)
+			}
+		}
+		b, err := readMax(f, max)
 		if err != nil {
 			return "", fmt.Errorf("Failed to read file: %s", err)
-		} else {
-			return string(data), nil
 		}
+		return b, nil
 	case '-':
 		if len(data) > 1 {
-			return data, nil
+			return checkMax(data, max)
 		}
-		var b bytes.Buffer
-		if _, err := io.Copy(&b, stdin); err != nil {
+		b, err := readMax(stdin, max)
+		if err != nil {
 			return "", fmt.Errorf("Failed to read stdin: %s", err)
 		}
-		return b.String(), nil
+		return b, nil
 	default:
-		return data, nil
+		return checkMax(data, max)
 	}
 }
+
+// readMax reads r in full, failing once more than max bytes have been read.
+func readMax(r io.Reader, max int64) (string, error) {
+	if max > 0 {
+		r = io.LimitReader(r, max+1)
+	}
+
+	var b bytes.Buffer
+	n, err := io.Copy(&b, r)
+	if err != nil {
+		return "", err
+	}
+	if max > 0 && n > max {
+		return "", errExceedsMax(max)
+	}
+	return b.String(), nil
+}
+
+func checkMax(data string, max int64) (string, error) {
+	if max > 0 && int64(len(data)) > max {
+		return "", errExceedsMax(max)
+	}
+	return data, nil
+}
+
+func errExceedsMax(max int64) error {
+	return fmt.Errorf("value exceeds the %d byte limit", max)
+}
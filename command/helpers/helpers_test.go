@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDataSourceMax(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "helpers")
+	defer os.RemoveAll(testDir)
+
+	small := filepath.Join(testDir, "small")
+	require.NoError(t, ioutil.WriteFile(small, []byte("0123456789"), 0644))
+
+	cases := []struct {
+		name  string
+		data  string
+		stdin string
+		max   int64
+		out   string
+		err   string
+	}{
+		{"empty", "", "", 5, "", ""},
+		{"raw", "abc", "", 5, "abc", ""},
+		{"raw too large", "abcdef", "", 5, "", "exceeds the 5 byte limit"},
+		{"raw dash prefix", "-abc", "", 5, "-abc", ""},
+		{"file", "@" + small, "", 10, "0123456789", ""},
+		{"file unlimited", "@" + small, "", 0, "0123456789", ""},
+		{"file too large", "@" + small, "", 9, "", "is 10 bytes which exceeds the 9 byte limit"},
+		{"file missing", "@" + filepath.Join(testDir, "nope"), "", 9, "", "Failed to read file"},
+		{"stdin", "-", "hello", 5, "hello", ""},
+		{"stdin too large", "-", "hello!", 5, "", "Failed to read stdin: value exceeds the 5 byte limit"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := LoadDataSourceMax(tc.data, strings.NewReader(tc.stdin), tc.max)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}